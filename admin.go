@@ -0,0 +1,278 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"path/filepath"
+	"power4/game"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------------- ADMINISTRATION ----------------
+// Zone /admin et API /api/admin/*, protégées par le jeton cfg.AdminToken (en-tête
+// "Authorization: Bearer <jeton>" ou mot de passe HTTP Basic). Sans jeton configuré,
+// l'administration est désactivée. Le navigateur renvoie seul le mot de passe Basic : les
+// actions (POST) authentifiées ainsi doivent venir d'une origine autorisée.
+
+var adminToken = cfg.AdminToken
+
+// Codes de fermeture WebSocket envoyés par l'administration
+const (
+	closeKicked       = 4000
+	closePartyDeleted = 4001
+)
+
+// clientInfo : informations d'administration sur une connexion WebSocket
+type clientInfo struct {
+	ID    string    `json:"id"`
+	Since time.Time `json:"since"`
+	Addr  string    `json:"addr"`
+}
+
+// adminOnly refuse la requête si le jeton d'administration est absent ou faux
+func adminOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if adminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		_, password, basic := r.BasicAuth()
+		if basic {
+			given = password
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(adminToken)) != 1 {
+			requestLogger(r).Warn("accès administrateur refusé", "path", r.URL.Path)
+			w.Header().Set("WWW-Authenticate", `Basic realm="power4-admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if basic && r.Method == http.MethodPost && !originAllowed(r) {
+			// Formulaire d'un autre site soumis avec le mot de passe mémorisé par le navigateur
+			requestLogger(r).Warn("action administrateur d'une origine refusée", "path", r.URL.Path, "origin", r.Header.Get("Origin"))
+			writeJSONError(w, http.StatusForbidden, "Origine non autorisée")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// adminClient : connexion listée dans l'administration
+type adminClient struct {
+	clientInfo
+	Seat string `json:"seat"`
+}
+
+// adminParty : résumé d'une partie pour l'administration
+type adminParty struct {
+	Code       string            `json:"code"`
+	Mode       string            `json:"mode"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+	Age        string            `json:"age"`
+	Version    int               `json:"version"`
+	Next       string            `json:"next"`
+	Finished   bool              `json:"finished"`
+	Winner     string            `json:"winner"`
+	Async      bool              `json:"async"`
+	Tournament string            `json:"tournament,omitempty"`
+	Seats      map[string]string `json:"seats"`
+	Bots       map[string]string `json:"bots,omitempty"`
+	Clients    []adminClient     `json:"clients"`
+}
+
+// summary résume la partie pour l'administration. p.Mu doit être verrouillé.
+func (p *Party) summary() adminParty {
+	a := adminParty{
+		Code: p.Code, Mode: p.State.Mode, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt,
+		Age:     time.Since(p.CreatedAt).Round(time.Second).String(),
+		Version: p.State.Version, Next: p.State.Next, Finished: p.State.Finished, Winner: p.State.Winner,
+		Async: p.Async, Tournament: p.Tournament, Seats: p.Seats, Bots: p.Bots,
+		Clients: []adminClient{},
+	}
+	for c := range p.Clients {
+		a.Clients = append(a.Clients, adminClient{clientInfo: p.ClientInfo[c], Seat: p.ClientTeam[c]})
+	}
+	sort.Slice(a.Clients, func(i, j int) bool { return a.Clients[i].Since.Before(a.Clients[j].Since) })
+	return a
+}
+
+// allParties retourne une copie de la liste des parties, sans garder partiesMu
+func allParties() []*Party {
+	partiesMu.Lock()
+	defer partiesMu.Unlock()
+	list := make([]*Party, 0, len(parties))
+	for _, p := range parties {
+		list = append(list, p)
+	}
+	return list
+}
+
+// adminLookup retourne la partie demandée (paramètre code) ou répond 404
+func adminLookup(w http.ResponseWriter, r *http.Request) *Party {
+	code := strings.ToUpper(r.FormValue("code"))
+	partiesMu.Lock()
+	p, ok := parties[code]
+	partiesMu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "Partie introuvable")
+		return nil
+	}
+	return p
+}
+
+func adminPartiesHandler(w http.ResponseWriter, r *http.Request) {
+	list := []adminParty{}
+	for _, p := range allParties() {
+		p.Mu.Lock()
+		list = append(list, p.summary())
+		p.Mu.Unlock()
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	writeJSON(w, list)
+}
+
+func adminPartyHandler(w http.ResponseWriter, r *http.Request) {
+	p := adminLookup(w, r)
+	if p == nil {
+		return
+	}
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	writeJSON(w, map[string]interface{}{"party": p.summary(), "state": p.State, "log": p.Log})
+}
+
+// adminFinishHandler termine la manche en cours (winner R, Y ou vide pour une nulle)
+func adminFinishHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	p := adminLookup(w, r)
+	if p == nil {
+		return
+	}
+	winner := strings.ToUpper(r.FormValue("winner"))
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if winner != "" && !contains(game.PlayersOf(p.State), winner) {
+		writeJSONError(w, http.StatusBadRequest, "Vainqueur invalide")
+		return
+	}
+	if p.State.Finished {
+		writeJSONError(w, http.StatusConflict, "La manche est déjà terminée")
+		return
+	}
+	p.State.Finished = true
+	p.State.Winner = winner
+	p.State.Version++
+	p.loggerFor(r).Warn("manche terminée par un administrateur", "winner", winner)
+	p.changed()
+	p.broadcast(map[string]interface{}{"type": "announcement", "text": "La partie a été terminée par un administrateur."})
+	p.broadcastState(map[string]interface{}{"type": "state", "blocked": p.BlockedColumn})
+	writeJSON(w, map[string]interface{}{"success": true})
+}
+
+// adminDeleteHandler ferme toutes les connexions de la partie et la supprime
+func adminDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	p := adminLookup(w, r)
+	if p == nil {
+		return
+	}
+	partiesMu.Lock()
+	delete(parties, p.Code)
+	partiesMu.Unlock()
+
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	p.stopBots()
+	for c := range p.Clients {
+		closeConn(c, closePartyDeleted, "Partie supprimée par un administrateur")
+	}
+	if p.Async {
+		if err := os.Remove(filepath.Join(dataDir, p.Code+".json")); err != nil && !os.IsNotExist(err) {
+			p.loggerFor(r).Error("suppression de la sauvegarde impossible", "err", err)
+		}
+	}
+	p.loggerFor(r).Warn("partie supprimée par un administrateur")
+	writeJSON(w, map[string]interface{}{"success": true})
+}
+
+// closeConn envoie un message de fermeture WebSocket puis ferme la connexion.
+// Le verrou de la partie doit être tenu (écriture sur la connexion).
+func closeConn(c *websocket.Conn, code int, reason string) {
+	msg := websocket.FormatCloseMessage(code, reason)
+	_ = c.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	_ = c.Close()
+}
+
+// adminKickHandler ferme la connexion identifiée par id
+func adminKickHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id := r.FormValue("id")
+	for _, p := range allParties() {
+		p.Mu.Lock()
+		for c, info := range p.ClientInfo {
+			if info.ID == id {
+				closeConn(c, closeKicked, "Connexion fermée par un administrateur")
+				p.loggerFor(r).Warn("client expulsé", "seat", p.ClientTeam[c], "client", id)
+				p.Mu.Unlock()
+				writeJSON(w, map[string]interface{}{"success": true})
+				return
+			}
+		}
+		p.Mu.Unlock()
+	}
+	writeJSONError(w, http.StatusNotFound, "Connexion introuvable")
+}
+
+// adminAnnounceHandler diffuse une annonce à tous les clients connectés
+func adminAnnounceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	text := strings.TrimSpace(r.FormValue("text"))
+	if text == "" {
+		writeJSONError(w, http.StatusBadRequest, "Annonce vide")
+		return
+	}
+	sent := announce(text)
+	requestLogger(r).Info("annonce diffusée", "clients", sent)
+	writeJSON(w, map[string]interface{}{"success": true, "clients": sent})
+}
+
+// announce envoie une annonce serveur à tous les clients et retourne leur nombre
+func announce(text string) int {
+	sent := 0
+	for _, p := range allParties() {
+		p.Mu.Lock()
+		p.broadcast(map[string]interface{}{"type": "announcement", "text": text})
+		sent += len(p.Clients)
+		p.Mu.Unlock()
+	}
+	return sent
+}
+
+func adminErrorsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, recentErrors.list())
+}
+
+func adminPageHandler(w http.ResponseWriter, r *http.Request) {
+	if err := pages.Execute(w, "admin.html", nil); err != nil {
+		requestLogger(r).Error("rendu du template impossible", "template", "admin.html", "err", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
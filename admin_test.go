@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAdminOnly(t *testing.T) {
+	ok := adminOnly(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	call := func(setup func(r *http.Request)) int {
+		r := httptest.NewRequest(http.MethodGet, "/api/admin/parties", nil)
+		setup(r)
+		w := httptest.NewRecorder()
+		ok(w, r)
+		return w.Code
+	}
+
+	defer func(saved string) { adminToken = saved }(adminToken)
+	adminToken = ""
+	if code := call(func(r *http.Request) {}); code != http.StatusNotFound {
+		t.Fatalf("administration sans jeton : %d, attendu 404", code)
+	}
+
+	adminToken = "sesame"
+	cases := map[string]struct {
+		setup func(r *http.Request)
+		want  int
+	}{
+		"sans identifiants": {func(r *http.Request) {}, http.StatusUnauthorized},
+		"mauvais jeton":     {func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+		"bearer":            {func(r *http.Request) { r.Header.Set("Authorization", "Bearer sesame") }, http.StatusNoContent},
+		"basic":             {func(r *http.Request) { r.SetBasicAuth("admin", "sesame") }, http.StatusNoContent},
+	}
+	for name, c := range cases {
+		if code := call(c.setup); code != c.want {
+			t.Fatalf("%s : %d, attendu %d", name, code, c.want)
+		}
+	}
+
+	// Actions : le mot de passe Basic seul ne suffit pas depuis un autre site
+	post := func(origin string, setup func(r *http.Request)) int {
+		r := httptest.NewRequest(http.MethodPost, "/api/admin/party/delete", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		setup(r)
+		w := httptest.NewRecorder()
+		ok(w, r)
+		return w.Code
+	}
+	basic := func(r *http.Request) { r.SetBasicAuth("admin", "sesame") }
+	bearer := func(r *http.Request) { r.Header.Set("Authorization", "Bearer sesame") }
+	posts := map[string]struct {
+		origin string
+		setup  func(r *http.Request)
+		want   int
+	}{
+		"basic, autre site":  {"https://evil.example", basic, http.StatusForbidden},
+		"basic, même site":   {"http://example.com", basic, http.StatusNoContent},
+		"basic, sans origin": {"", basic, http.StatusNoContent},
+		"bearer, autre site": {"https://evil.example", bearer, http.StatusNoContent},
+	}
+	for name, c := range posts {
+		if code := post(c.origin, c.setup); code != c.want {
+			t.Fatalf("POST %s : %d, attendu %d", name, code, c.want)
+		}
+	}
+}
+
+func TestAdminFinishAnyPlayer(t *testing.T) {
+	finish := func(p *Party, winner string) int {
+		form := url.Values{"code": {p.Code}, "winner": {winner}}
+		r := httptest.NewRequest(http.MethodPost, "/api/admin/party/finish", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		adminFinishHandler(w, r)
+		return w.Code
+	}
+	p := createParty(t, "variant=classique&players=4")
+	if code := finish(p, "X"); code != http.StatusBadRequest || p.State.Finished {
+		t.Fatalf("vainqueur inconnu : %d", code)
+	}
+	if code := finish(p, "B"); code != http.StatusOK || p.State.Winner != "B" {
+		t.Fatalf("vainqueur B : %d, winner=%q", code, p.State.Winner)
+	}
+	// B n'est pas de la partie à deux joueurs
+	if code := finish(createParty(t, "variant=classique"), "B"); code != http.StatusBadRequest {
+		t.Fatalf("B dans une partie à deux : %d", code)
+	}
+}
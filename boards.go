@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"power4/game"
+)
+
+// ---------------- MODÈLES DE PLATEAU ----------------
+// Un modèle fixe la forme du plateau (trous), ses cases bloquées et des pions posés
+// d'avance. Il se choisit à la création (template=Croix ou template={...} au format JSON)
+// et se dessine dans l'éditeur (/editor), qui importe et exporte ce même format.
+
+// templatesHandler liste les modèles par défaut au format JSON d'import/export
+func templatesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{"templates": game.Templates})
+}
+
+// editorPageHandler affiche l'éditeur de modèles de plateau
+func editorPageHandler(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Templates []game.Template
+		Board     boardLimits
+		Variants  []string
+	}{game.Templates, cfg.Board, templateVariants()}
+	if err := pages.Execute(w, "editor.html", data); err != nil {
+		requestLogger(r).Error("rendu du template impossible", "template", "editor.html", "err", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// templateVariants liste les variantes jouables sur un modèle de plateau
+func templateVariants() []string {
+	var variants []string
+	for _, v := range game.Variants() {
+		if v != "popout" && v != "exponentiel" {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
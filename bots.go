@@ -0,0 +1,261 @@
+package main
+
+import (
+	"net/http"
+	"power4/engine"
+	"power4/game"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------- MOTEURS ASSIS DANS UNE PARTIE ----------------
+
+// localEngine : moteur intégré au serveur, toujours disponible
+const localEngine = "local"
+
+// registeredEngines : nom -> commande et arguments, tirés de cfg.Engines au démarrage
+var registeredEngines = engineCommands(cfg.Engines.Commands)
+
+// botMoveTime : temps de réflexion accordé aux moteurs à chaque coup (cfg.Engines.MoveTime)
+var botMoveTime = cfg.Engines.MoveTime.Duration
+
+// parseEngines lit P4_ENGINES : "nom=commande args,nom2=..."
+func parseEngines(s string) map[string]string {
+	engines := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		name, cmd, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || len(strings.Fields(cmd)) == 0 {
+			continue
+		}
+		engines[name] = strings.TrimSpace(cmd)
+	}
+	return engines
+}
+
+// engineCommands découpe chaque commande de moteur en programme et arguments
+func engineCommands(commands map[string]string) map[string][]string {
+	engines := make(map[string][]string, len(commands))
+	for name, cmd := range commands {
+		engines[name] = strings.Fields(cmd)
+	}
+	return engines
+}
+
+// engineNames liste les moteurs disponibles, le moteur intégré en premier
+func engineNames() []string {
+	names := make([]string, 0, len(registeredEngines)+1)
+	for name := range registeredEngines {
+		if name != localEngine {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{localEngine}, names...)
+}
+
+// botProcess : moteur assis à une place. Le processus d'un moteur externe est lancé au
+// premier coup puis gardé jusqu'à ce que le moteur quitte sa place.
+type botProcess struct {
+	name string
+	mu   sync.Mutex
+	e    *engine.Engine // nil : pas encore lancé, arrêté ou moteur intégré
+}
+
+// bestMove demande un coup au moteur pour la position st
+func (b *botProcess) bestMove(st game.GameState) (int, error) {
+	if b.name == localEngine {
+		return game.BestMove(st, botMoveTime), nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.e == nil {
+		cmd := registeredEngines[b.name]
+		e, err := engine.Start(cmd[0], cmd[1:]...)
+		if err != nil {
+			return -1, err
+		}
+		b.e = e
+	}
+	col, err := b.e.BestMove(engine.FormatPosition(st), engine.Clock{MoveTime: botMoveTime}, st.Next, 2*time.Second)
+	if err != nil {
+		// Une réponse en retard fausserait le coup suivant : le processus est relancé
+		_ = b.e.Close()
+		b.e = nil
+	}
+	return col, err
+}
+
+// close arrête le processus du moteur, s'il tourne
+func (b *botProcess) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.e != nil {
+		_ = b.e.Close()
+		b.e = nil
+	}
+}
+
+// botFor retourne le moteur assis à la place team, en gardant son processus d'un coup à
+// l'autre. p.Mu doit être verrouillé.
+func (p *Party) botFor(team string) *botProcess {
+	name := p.Bots[team]
+	if b := p.botProcs[team]; b != nil && b.name == name {
+		return b
+	}
+	p.stopBot(team)
+	if p.botProcs == nil {
+		p.botProcs = make(map[string]*botProcess)
+	}
+	b := &botProcess{name: name}
+	p.botProcs[team] = b
+	return b
+}
+
+// stopBot arrête le processus du moteur assis à la place team. p.Mu doit être verrouillé.
+func (p *Party) stopBot(team string) {
+	if b := p.botProcs[team]; b != nil {
+		delete(p.botProcs, team)
+		go b.close() // Le moteur peut être en pleine réflexion
+	}
+}
+
+// stopBots arrête les processus de tous les moteurs de la partie. p.Mu doit être verrouillé.
+func (p *Party) stopBots() {
+	for team := range p.botProcs {
+		p.stopBot(team)
+	}
+}
+
+// scheduleBot lance la réflexion du moteur assis à la place du joueur au trait.
+// p.Mu doit être verrouillé.
+func (p *Party) scheduleBot() {
+	name := p.Bots[p.State.Next]
+	if name == "" || p.State.Finished || p.botThinking || !game.Gravity(p.State) {
+		return
+	}
+	p.botThinking = true
+	bot := p.botFor(p.State.Next)
+	// Information cachée (brouillard) : le moteur ne voit que ce que voit son siège, mais
+	// garde les positions déjà rencontrées (règle de répétition)
+	st, version := game.View(p.State, p.State.Next), p.State.Version
+	seat := p.turnSeat() // En 2 contre 2, le moteur joue pour le siège au trait de sa couleur
+	go func() {
+		col, err := bot.bestMove(st)
+
+		p.Mu.Lock()
+		defer p.Mu.Unlock()
+		p.botThinking = false
+		if err != nil {
+			p.logger().Error("moteur en échec", "seat", st.Next, "engine", name, "err", err)
+			return
+		}
+		if p.State.Version != version {
+			p.scheduleBot() // La position a changé pendant la réflexion
+			return
+		}
+		booster, err := p.playColumn(seat, col)
+		if err == errIllegalMove {
+			// Le moteur ne connaît que les pions lâchés : sans colonne libre (PopOut), il joue le premier coup légal
+			if moves := game.VariantOf(p.State).LegalMoves(p.State); len(moves) > 0 {
+				booster, err = p.play(seat, moves[0])
+			}
+		}
+		if err == errColumnBlocked {
+			// Le moteur ne connaît pas les boosters : la colonne est débloquée, il rejoue
+			p.scheduleBot()
+			return
+		}
+		if err != nil {
+			p.logger().Warn("coup du moteur refusé", "seat", st.Next, "engine", name, "col", col, "err", err)
+			return
+		}
+		p.broadcastMove(nil, booster)
+	}()
+}
+
+// partyBotHandler assoit un moteur (paramètre engine) à la place de l'équipe team ;
+// un moteur vide libère la place. Une place déjà attribuée demande son jeton de siège
+// (paramètre token) ; une place libre est prise par le moteur et son jeton est retourné.
+func partyBotHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		writeJSON(w, map[string]interface{}{"engines": engineNames()})
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	code := strings.ToUpper(r.FormValue("code"))
+	team := r.FormValue("team")
+	name := r.FormValue("engine")
+
+	partiesMu.Lock()
+	p, ok := parties[code]
+	partiesMu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "Partie introuvable")
+		return
+	}
+	if _, known := registeredEngines[name]; name != "" && name != localEngine && !known {
+		writeJSONError(w, http.StatusBadRequest, "Moteur inconnu")
+		return
+	}
+
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if !contains(game.PlayersOf(p.State), team) {
+		writeJSONError(w, http.StatusBadRequest, "Équipe invalide")
+		return
+	}
+	if p.isConcealed() && name != "" && name != localEngine {
+		// Le protocole des moteurs ne décrit pas les cases cachées
+		writeJSONError(w, http.StatusBadRequest, "Seul le moteur intégré joue cette variante")
+		return
+	}
+	if (len(game.PlayersOf(p.State)) > 2 || p.State.Template != nil) && name != "" && name != localEngine {
+		// Le protocole ne connaît que R, Y et les cases vides : ni troisième joueur, ni pion
+		// neutralisé, ni case bloquée ou trou d'un modèle de plateau
+		writeJSONError(w, http.StatusBadRequest, "Seul le moteur intégré joue à plus de deux ou sur un modèle de plateau")
+		return
+	}
+	if !game.Gravity(p.State) && name != "" {
+		// Les moteurs ne répondent qu'une colonne (ni case, ni coup d'ouverture swap2)
+		writeJSONError(w, http.StatusBadRequest, "Les moteurs ne jouent que les variantes à gravité")
+		return
+	}
+	if p.isSimultaneous() && name != "" {
+		writeJSONError(w, http.StatusBadRequest, "Les moteurs ne jouent pas les coups simultanés")
+		return
+	}
+	token := requestToken(r, code)
+	if p.seatOwned(team) && p.Tokens[token] != team {
+		writeJSONError(w, http.StatusForbidden, "Jeton de siège invalide")
+		return
+	}
+	if p.Bots == nil {
+		p.Bots = make(map[string]string)
+	}
+	resp := map[string]interface{}{"success": true}
+	if name == "" {
+		delete(p.Bots, team)
+		p.stopBot(team)
+		if strings.HasPrefix(p.Seats[team], "🤖 ") {
+			// La place redevient libre : le jeton du moteur ne sert plus
+			delete(p.Seats, team)
+			delete(p.Tokens, token)
+		}
+	} else {
+		if !p.seatOwned(team) {
+			token = p.takeSeat(team, "", "")
+			resp["token"] = token
+		}
+		p.Bots[team] = name
+		p.Seats[team] = "🤖 " + name
+		p.loggerFor(r).Info("moteur assis", "seat", team, "engine", name)
+	}
+	p.changed()
+	resp["bots"] = p.Bots
+	writeJSON(w, resp)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func postBot(form url.Values) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodPost, "/api/party/bot", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	partyBotHandler(w, req)
+	var resp map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w, resp
+}
+
+func TestPartyBotRequiresSeatToken(t *testing.T) {
+	p, token := newAsyncParty(t)
+
+	// Place de R : il faut son jeton
+	for _, bad := range []string{"", "inconnu"} {
+		if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"R"}, "engine": {localEngine}, "token": {bad}}); w.Code != http.StatusForbidden {
+			t.Errorf("jeton %q : code %d", bad, w.Code)
+		}
+	}
+	if p.Bots["R"] != "" {
+		t.Fatal("moteur assis sans jeton")
+	}
+
+	// Place libre de Y : le moteur la prend et son jeton est retourné
+	w, resp := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "engine": {localEngine}})
+	botToken, _ := resp["token"].(string)
+	if w.Code != http.StatusOK || botToken == "" || p.Tokens[botToken] != "Y" {
+		t.Fatalf("place libre : code %d (%v)", w.Code, resp)
+	}
+	// Sans ce jeton, personne ne retire le moteur
+	if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "token": {token}}); w.Code != http.StatusForbidden {
+		t.Errorf("jeton de R sur la place de Y : code %d", w.Code)
+	}
+	if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "token": {botToken}}); w.Code != http.StatusOK {
+		t.Fatalf("retrait du moteur : code %d", w.Code)
+	}
+	if p.Bots["Y"] != "" || p.Seats["Y"] != "" || p.seatOwned("Y") {
+		t.Fatalf("place de Y non libérée : bots=%v sièges=%v", p.Bots, p.Seats)
+	}
+}
+
+func TestPartyBotAnyPlayer(t *testing.T) {
+	p := createParty(t, "variant=classique&players=3")
+	if w, resp := postBot(url.Values{"code": {p.Code}, "team": {"G"}, "engine": {localEngine}}); w.Code != http.StatusOK || p.Bots["G"] != localEngine {
+		t.Fatalf("moteur pour G : code %d (%v)", w.Code, resp)
+	}
+	if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"B"}, "engine": {localEngine}}); w.Code != http.StatusBadRequest {
+		t.Fatalf("B absent de la partie : code %d", w.Code)
+	}
+}
+
+func TestPartyBotRefusedWithoutGravity(t *testing.T) {
+	for _, query := range []string{"variant=gomoku", "variant=gomoku&opt.opening=swap2"} {
+		p := createParty(t, query)
+		if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "engine": {localEngine}}); w.Code != http.StatusBadRequest {
+			t.Errorf("%s : code %d", query, w.Code)
+		}
+		if len(p.Bots) != 0 {
+			t.Fatalf("%s : moteur assis %v", query, p.Bots)
+		}
+	}
+}
+
+func TestBotProcessKeptBetweenMoves(t *testing.T) {
+	// Moteur minimal : note chaque lancement et joue toujours la colonne 3
+	dir := t.TempDir()
+	starts := filepath.Join(dir, "starts")
+	script := filepath.Join(dir, "engine.sh")
+	body := "#!/bin/sh\necho start >> " + starts + "\n" +
+		"while read line; do case \"$line\" in p4e) echo p4eok;; go*) echo 'bestmove 3';; quit) exit 0;; esac; done\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	prev := registeredEngines
+	registeredEngines = map[string][]string{"sh": {"/bin/sh", script}}
+	t.Cleanup(func() { registeredEngines = prev })
+
+	p := createParty(t, "variant=classique")
+	p.Mu.Lock()
+	p.Bots = map[string]string{"Y": "sh"}
+	bot := p.botFor("Y")
+	p.Mu.Unlock()
+	for i := 0; i < 3; i++ {
+		if col, err := bot.bestMove(p.State); err != nil || col != 3 {
+			t.Fatalf("coup %d : col=%d err=%v", i, col, err)
+		}
+	}
+	p.Mu.Lock()
+	if p.botFor("Y") != bot {
+		t.Error("le moteur assis a changé")
+	}
+	p.Mu.Unlock()
+	bot.close()
+
+	data, err := os.ReadFile(starts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "start"); n != 1 {
+		t.Fatalf("%d lancements du moteur pour trois coups", n)
+	}
+}
+
+func TestPartyBotExternalOnlyForTwoPlayers(t *testing.T) {
+	prev := registeredEngines
+	registeredEngines = map[string][]string{"ext": {"/bin/true"}}
+	t.Cleanup(func() { registeredEngines = prev })
+
+	for _, query := range []string{"variant=classique&players=3", "variant=classique&template=Croix"} {
+		p := createParty(t, query)
+		if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "engine": {"ext"}}); w.Code != http.StatusBadRequest {
+			t.Errorf("%s : moteur externe, code %d", query, w.Code)
+		}
+		if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "engine": {localEngine}}); w.Code != http.StatusOK {
+			t.Errorf("%s : moteur intégré, code %d", query, w.Code)
+		}
+	}
+	p := createParty(t, "variant=classique")
+	if w, _ := postBot(url.Values{"code": {p.Code}, "team": {"Y"}, "engine": {"ext"}}); w.Code != http.StatusOK {
+		t.Errorf("deux joueurs : moteur externe, code %d", w.Code)
+	}
+}
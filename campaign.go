@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"power4/game"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------------- PROGRESSION DU MODE EXPONENTIEL ----------------
+
+// CampaignCurve décrit comment le plateau grandit d'un niveau à l'autre
+type CampaignCurve struct {
+	Name     string `json:"name"`
+	RowStep  int    `json:"rowStep"`  // Lignes ajoutées à chaque niveau
+	ColStep  int    `json:"colStep"`  // Colonnes ajoutées à chaque niveau
+	WinEvery int    `json:"winEvery"` // +1 pion à aligner tous les N niveaux (0 = jamais)
+	MaxWin   int    `json:"maxWin"`   // Nombre maximal de pions à aligner
+}
+
+// Courbes prédéfinies, choisies avec le paramètre "curve" à la création
+var campaignCurves = map[string]CampaignCurve{
+	"lineaire":  {Name: "lineaire", RowStep: 1, ColStep: 1},
+	"rapide":    {Name: "rapide", RowStep: 2, ColStep: 2},
+	"puissance": {Name: "puissance", RowStep: 1, ColStep: 1, WinEvery: 3, MaxWin: 6},
+}
+
+// Campaign suit la progression d'une partie exponentielle : niveau, taille du plateau
+// et score cumulé sur tous les niveaux.
+type Campaign struct {
+	Level    int            `json:"level"`
+	Curve    CampaignCurve  `json:"curve"`
+	Score    map[string]int `json:"score"`    // Points cumulés (un niveau gagné rapporte son numéro)
+	Recorded bool           `json:"recorded"` // Résultat du niveau en cours déjà compté
+	Finished bool           `json:"finished"` // Le plateau a atteint sa taille maximale et le dernier niveau est joué
+}
+
+var errCampaignOver = errors.New("La campagne est terminée : plateau maximal atteint")
+
+// newCampaignFromQuery lit la courbe (curve, rowstep, colstep, winevery, maxwin) dans l'URL
+func newCampaignFromQuery(q map[string][]string) *Campaign {
+	get := func(k string) string {
+		if v := q[k]; len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+	curve, ok := campaignCurves[get("curve")]
+	if !ok {
+		curve = campaignCurves["lineaire"]
+	}
+	override := func(dst *int, key string, min int) {
+		if v, err := strconv.Atoi(get(key)); err == nil && v >= min {
+			*dst = v
+			curve.Name = "personnalisee"
+		}
+	}
+	override(&curve.RowStep, "rowstep", 0)
+	override(&curve.ColStep, "colstep", 0)
+	override(&curve.WinEvery, "winevery", 0)
+	override(&curve.MaxWin, "maxwin", 3)
+	if curve.RowStep == 0 && curve.ColStep == 0 {
+		curve.RowStep, curve.ColStep = 1, 1
+	}
+	return &Campaign{
+		Level: 1,
+		Curve: curve,
+		Score: map[string]int{"R": 0, "Y": 0},
+	}
+}
+
+// record ajoute au score cumulé le résultat du niveau terminé
+func (c *Campaign) record(st game.GameState) {
+	if c == nil || !st.Finished || c.Recorded {
+		return
+	}
+	c.Recorded = true
+	if st.Winner != "" {
+		c.Score[st.Winner] += c.Level
+	}
+	// Fin de campagne quand le plateau ne peut plus grandir
+	if rows, cols, _ := c.nextSize(st); rows == st.Rows && cols == st.Cols {
+		c.Finished = true
+	}
+}
+
+// nextSize calcule les dimensions et le nombre de pions à aligner du niveau suivant. Le
+// plateau grandit jusqu'aux tailles maximales de cfg.Board, sans jamais rétrécir.
+func (c *Campaign) nextSize(st game.GameState) (rows, cols, winLength int) {
+	rows = max(st.Rows, min(cfg.Board.MaxRows, st.Rows+c.Curve.RowStep))
+	cols = max(st.Cols, min(cfg.Board.MaxCols, st.Cols+c.Curve.ColStep))
+	winLength = st.WinLength
+	if c.Curve.WinEvery > 0 && (c.Level+1)%c.Curve.WinEvery == 0 {
+		winLength++
+	}
+	if c.Curve.MaxWin > 0 {
+		winLength = min(winLength, c.Curve.MaxWin)
+	}
+	// Il faut toujours pouvoir aligner les pions sur le plateau
+	winLength = min(winLength, rows, cols)
+	return rows, cols, winLength
+}
+
+// nextLevel passe au niveau suivant en agrandissant le plateau. p.Mu doit être verrouillé.
+func (p *Party) nextLevel() error {
+	c := p.Campaign
+	if c == nil || !p.State.Finished {
+		return errIllegalMove
+	}
+	if c.Finished {
+		return errCampaignOver
+	}
+	rows, cols, winLength := c.nextSize(p.State)
+	c.Level++
+	c.Recorded = false
+	p.startNextGame()
+	p.State.Rows, p.State.Cols, p.State.WinLength = rows, cols, winLength
+	p.initBoard()
+	return nil
+}
+
+// handleNextLevel répond au message WebSocket {"type":"next-level"}
+func handleNextLevel(p *Party, conn *websocket.Conn) {
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	if seatColor(p.ClientTeam[conn]) == "" {
+		return
+	}
+	if err := p.nextLevel(); err != nil {
+		_ = conn.WriteJSON(map[string]interface{}{"type": "error", "message": err.Error()})
+		return
+	}
+	p.changed()
+	p.logger().Info("niveau suivant", "level", p.Campaign.Level, "rows", p.State.Rows, "cols", p.State.Cols, "win_length", p.State.WinLength)
+	p.broadcastState(map[string]interface{}{"type": "state", "blocked": p.BlockedColumn, "campaign": p.Campaign})
+}
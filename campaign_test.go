@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/url"
+	"power4/game"
+	"testing"
+)
+
+func TestCampaignCurves(t *testing.T) {
+	st := game.GameState{Rows: 6, Cols: 7, WinLength: 4}
+	for _, tc := range []struct {
+		curve                 string
+		level                 int
+		rows, cols, winLength int
+	}{
+		{"lineaire", 1, 7, 8, 4},
+		{"lineaire", 2, 7, 8, 4},
+		{"rapide", 1, 8, 9, 4},
+		{"puissance", 1, 7, 8, 4},
+		{"puissance", 2, 7, 8, 5}, // +1 pion tous les 3 niveaux
+		{"", 1, 7, 8, 4},          // Courbe inconnue : linéaire
+	} {
+		c := newCampaignFromQuery(url.Values{"curve": {tc.curve}})
+		c.Level = tc.level
+		if r, co, w := c.nextSize(st); r != tc.rows || co != tc.cols || w != tc.winLength {
+			t.Errorf("%s niveau %d : %dx%d, %d pions ; attendu %dx%d, %d", tc.curve, tc.level, r, co, w, tc.rows, tc.cols, tc.winLength)
+		}
+	}
+}
+
+func TestCampaignWinLengthCap(t *testing.T) {
+	c := newCampaignFromQuery(url.Values{"curve": {"puissance"}})
+	c.Level = 5
+	if _, _, w := c.nextSize(game.GameState{Rows: 10, Cols: 10, WinLength: 6}); w != 6 {
+		t.Fatalf("plafond maxWin : %d pions", w)
+	}
+	// Le plateau limite aussi la longueur d'alignement
+	c = newCampaignFromQuery(url.Values{"rowstep": {"0"}, "colstep": {"1"}, "winevery": {"1"}})
+	if r, _, w := c.nextSize(game.GameState{Rows: 4, Cols: 4, WinLength: 4}); r != 4 || w != 4 {
+		t.Fatalf("plateau de 4 rangées : %d rangées, %d pions", r, w)
+	}
+}
+
+func TestCampaignFinishesAtMaxBoard(t *testing.T) {
+	c := newCampaignFromQuery(url.Values{"curve": {"rapide"}})
+	c.Level = 3
+	c.record(game.GameState{Rows: 14, Cols: 15, WinLength: 4, Finished: true, Winner: "Y"})
+	if c.Finished || c.Score["Y"] != 3 {
+		t.Fatalf("14x15 : finished=%v score=%v", c.Finished, c.Score)
+	}
+	c.Recorded = false
+	c.record(game.GameState{Rows: 15, Cols: 15, WinLength: 4, Finished: true})
+	if !c.Finished || c.Score["Y"] != 3 {
+		t.Fatalf("15x15 : finished=%v score=%v", c.Finished, c.Score)
+	}
+}
+
+func TestNextLevel(t *testing.T) {
+	p := createParty(t, "variant=exponentiel&curve=rapide")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	rows, cols := p.State.Rows, p.State.Cols
+	if err := p.nextLevel(); err != errIllegalMove {
+		t.Fatalf("niveau en cours : %v", err)
+	}
+	p.State.Finished, p.State.Winner = true, "R"
+	p.changed()
+	if err := p.nextLevel(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Campaign.Level != 2 || p.Campaign.Score["R"] != 1 || p.State.Rows != rows+2 || p.State.Cols != cols+2 || p.State.Finished {
+		t.Fatalf("niveau 2 : level=%d score=%v %dx%d", p.Campaign.Level, p.Campaign.Score, p.State.Rows, p.State.Cols)
+	}
+
+	p.Campaign.Finished = true
+	p.State.Finished = true
+	if err := p.nextLevel(); err != errCampaignOver {
+		t.Fatalf("campagne terminée : %v", err)
+	}
+}
+
+func TestCampaignStopsAtConfiguredMax(t *testing.T) {
+	saved := cfg
+	t.Cleanup(func() { cfg = saved })
+	cfg.Board.MaxRows, cfg.Board.MaxCols = 8, 9
+	c := newCampaignFromQuery(url.Values{"curve": {"rapide"}})
+	if r, cl, _ := c.nextSize(game.GameState{Rows: 7, Cols: 8, WinLength: 4}); r != 8 || cl != 9 {
+		t.Fatalf("plateau suivant %dx%d, attendu 8x9", r, cl)
+	}
+	c.record(game.GameState{Rows: 8, Cols: 9, WinLength: 4, Finished: true})
+	if !c.Finished {
+		t.Fatal("campagne non terminée au plateau maximal de la configuration")
+	}
+}
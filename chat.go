@@ -0,0 +1,284 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------------- CHAT, RÉACTIONS ET MODÉRATION ----------------
+
+// Réactions rapides autorisées (dans l'ordre d'affichage)
+var chatReactionList = []string{"👍", "👏", "😂", "😮", "😢", "🔥"}
+
+var chatReactions = func() map[string]bool {
+	m := make(map[string]bool, len(chatReactionList))
+	for _, r := range chatReactionList {
+		m[r] = true
+	}
+	return m
+}()
+
+var (
+	errChatEmpty     = errors.New("Message vide")
+	errChatTooLong   = errors.New("Message trop long")
+	errChatRate      = errors.New("Trop de messages, attends un peu")
+	errChatBadEmoji  = errors.New("Réaction inconnue")
+	errChatSpectator = errors.New("Les spectateurs ne peuvent pas écrire")
+	errChatTeamOnly  = errors.New("Le chat d'équipe est réservé aux joueurs du mode 2 contre 2")
+)
+
+// chatLimiter mémorise l'heure des derniers messages de chaque connexion
+type chatLimiter struct {
+	mu   sync.Mutex
+	sent map[*websocket.Conn][]time.Time
+}
+
+var chatLimits = &chatLimiter{sent: make(map[*websocket.Conn][]time.Time)}
+
+func (l *chatLimiter) allow(conn *websocket.Conn, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	recent := l.sent[conn][:0]
+	for _, t := range l.sent[conn] {
+		if now.Sub(t) < cfg.Chat.RateWindow.Duration {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= cfg.Chat.RateCount {
+		l.sent[conn] = recent
+		return false
+	}
+	l.sent[conn] = append(recent, now)
+	return true
+}
+
+func (l *chatLimiter) forget(conn *websocket.Conn) {
+	l.mu.Lock()
+	delete(l.sent, conn)
+	l.mu.Unlock()
+}
+
+// Liste de mots filtrés : cfg.Chat.BannedWords et/ou cfg.Chat.BannedFile (un mot par ligne)
+var bannedWords = loadBannedWords(cfg.Chat)
+
+func loadBannedWords(c chatConfig) []string {
+	var words []string
+	for _, w := range c.BannedWords {
+		if w = strings.TrimSpace(strings.ToLower(w)); w != "" {
+			words = append(words, w)
+		}
+	}
+	if f := c.BannedFile; f != "" {
+		if data, err := os.ReadFile(f); err == nil {
+			for _, w := range strings.Split(string(data), "\n") {
+				if w = strings.TrimSpace(strings.ToLower(w)); w != "" && !strings.HasPrefix(w, "#") {
+					words = append(words, w)
+				}
+			}
+		}
+	}
+	return words
+}
+
+// filterProfanity remplace chaque mot interdit par des astérisques (sans tenir compte de la casse).
+// La comparaison se fait rune par rune sur le texte d'origine : passer en minuscules peut
+// changer la longueur en octets d'un caractère (Ⱥ, ȿ...).
+func filterProfanity(text string, words []string) string {
+	for _, w := range words {
+		n := utf8.RuneCountInString(w)
+		if n == 0 {
+			continue
+		}
+		var b strings.Builder
+		for i := 0; i < len(text); {
+			if end := runesLen(text[i:], n); end > 0 && strings.EqualFold(text[i:i+end], w) {
+				b.WriteString(strings.Repeat("*", n))
+				i += end
+				continue
+			}
+			_, size := utf8.DecodeRuneInString(text[i:])
+			b.WriteString(text[i : i+size])
+			i += size
+		}
+		text = b.String()
+	}
+	return text
+}
+
+// runesLen retourne la longueur en octets des n premières runes de s, -1 si s en compte moins
+func runesLen(s string, n int) int {
+	end := 0
+	for ; n > 0; n-- {
+		if end >= len(s) {
+			return -1
+		}
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+	return end
+}
+
+// seatName retourne le nom affiché pour une équipe (ou un siège du mode 2 contre 2)
+func (p *Party) seatName(team string) string {
+	if name := p.Seats[team]; name != "" {
+		return name
+	}
+	switch team {
+	case "R":
+		return playerNames[0]
+	case "Y":
+		return playerNames[1]
+	case "G":
+		return "Joueur vert"
+	case "B":
+		return "Joueur bleu"
+	case "R1", "R2":
+		return "Rouge " + team[1:]
+	case "Y1", "Y2":
+		return "Jaune " + team[1:]
+	}
+	return "Spectateur"
+}
+
+// chatHistory retourne les derniers messages et réactions publics de la partie (les messages
+// d'équipe ne sont pas réaffichés). p.Mu doit être verrouillé.
+func (p *Party) chatHistory() []LogEntry {
+	var history []LogEntry
+	for _, e := range p.Log {
+		if (e.Kind == "chat" || e.Kind == "reaction") && e.Scope == "" {
+			history = append(history, e)
+		}
+	}
+	if len(history) > cfg.Chat.HistorySize {
+		history = history[len(history)-cfg.Chat.HistorySize:]
+	}
+	return history
+}
+
+// chatLine : message réaffiché sur la page de jeu, avec le nom et la couleur de son auteur
+type chatLine struct {
+	LogEntry
+	Color string
+	Name  string
+}
+
+// chatLines prépare l'historique du chat pour la page de jeu. p.Mu doit être verrouillé.
+func (p *Party) chatLines() []chatLine {
+	var lines []chatLine
+	for _, e := range p.chatHistory() {
+		color := seatColor(e.Team)
+		if color == "" {
+			color = e.Team
+		}
+		lines = append(lines, chatLine{LogEntry: e, Color: color, Name: p.seatName(e.Team)})
+	}
+	return lines
+}
+
+// moveCount compte les coups joués, de toute sorte (pion lâché, retiré en PopOut, éclaireur,
+// coup d'ouverture...) : l'historique contient aussi le chat et les revanches
+func (p *Party) moveCount() int {
+	n := 0
+	for _, e := range p.Log {
+		switch e.Kind {
+		case "chat", "reaction", "rematch":
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// handleChat diffuse un message ou une réaction (kind "chat" ou "reaction"). En 2 contre 2,
+// teamOnly réserve le message aux deux partenaires.
+func handleChat(p *Party, conn *websocket.Conn, kind, text string, teamOnly bool) {
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	team := p.ClientTeam[conn]
+	entry, err := p.addChat(team, kind, text)
+	if err == nil && teamOnly {
+		if p.Teams == nil || !p.Teams.isSeat(team) {
+			err = errChatTeamOnly
+		}
+		entry.Scope = "team"
+	}
+	if err == nil && !chatLimits.allow(conn, entry.At) {
+		err = errChatRate
+	}
+	if err != nil {
+		_ = conn.WriteJSON(map[string]interface{}{
+			"type":    "chat-error",
+			"message": err.Error(),
+		})
+		return
+	}
+	p.Log = append(p.Log, entry)
+	p.changed()
+
+	msg := map[string]interface{}{
+		"type":  kind,
+		"team":  entry.Team,
+		"color": seatColor(entry.Team),
+		"name":  p.seatName(entry.Team),
+		"text":  entry.Text,
+		"at":    entry.At,
+	}
+	if entry.Scope != "" {
+		msg["scope"] = entry.Scope
+	}
+	for c := range p.Clients {
+		if p.Muted[c][entry.Team] || p.Muted[c][seatColor(entry.Team)] {
+			continue
+		}
+		if entry.Scope == "team" && seatColor(p.ClientTeam[c]) != seatColor(entry.Team) {
+			continue
+		}
+		_ = c.WriteJSON(msg)
+	}
+}
+
+// addChat valide un message et prépare l'entrée d'historique correspondante
+func (p *Party) addChat(team, kind, text string) (LogEntry, error) {
+	entry := LogEntry{At: time.Now(), Kind: kind, Team: team}
+	if team == "S" && kind == "chat" {
+		return entry, errChatSpectator
+	}
+	switch kind {
+	case "reaction":
+		if !chatReactions[text] {
+			return entry, errChatBadEmoji
+		}
+	default:
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return entry, errChatEmpty
+		}
+		if utf8.RuneCountInString(text) > cfg.Chat.MaxLength {
+			return entry, errChatTooLong
+		}
+		text = filterProfanity(text, bannedWords)
+	}
+	entry.Text = text
+	return entry, nil
+}
+
+// setMute masque (ou réaffiche) pour cette connexion les messages d'une équipe
+func setMute(p *Party, conn *websocket.Conn, team string, muted bool) {
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if p.Muted[conn] == nil {
+		p.Muted[conn] = make(map[string]bool)
+	}
+	if muted {
+		p.Muted[conn][team] = true
+	} else {
+		delete(p.Muted[conn], team)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestFilterProfanity(t *testing.T) {
+	words := []string{"merde"}
+	for _, tc := range []struct{ in, want string }{
+		{"Oh MERDE alors", "Oh ***** alors"},
+		{"merdemerde", "**********"},
+		// Ⱥ fait 2 octets, sa minuscule 3 : les positions ne doivent pas venir du texte en minuscules
+		{"ȺȺȺȺȺȺȺȺȺȺȺȺ merde", "ȺȺȺȺȺȺȺȺȺȺȺȺ *****"},
+		{"ȺȺ MerDE ȿ", "ȺȺ ***** ȿ"},
+		{"mer", "mer"},
+	} {
+		if got := filterProfanity(tc.in, words); got != tc.want {
+			t.Errorf("filterProfanity(%q) = %q, attendu %q", tc.in, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+// Commande arena : fait s'affronter des moteurs parlant le protocole p4e (voir package
+// engine) en toutes rondes, avec contrôle du temps, ouvertures aléatoires jouées avec
+// les deux couleurs et statistiques SPRT.
+//
+// Exemple :
+//
+//	go run ./cmd/arena -engine fort=./power4-engine -engine faible="./power4-engine -depth 2" \
+//	    -games 100 -tc 5+0.05 -openingplies 2 -sprt 0,50
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"power4/engine"
+	"power4/game"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// engineSpec : moteur déclaré avec -engine nom=chemin [arguments]
+type engineSpec struct {
+	Name string
+	Path string
+	Args []string
+}
+
+type engineFlags []engineSpec
+
+func (f *engineFlags) String() string { return fmt.Sprint(*f) }
+
+func (f *engineFlags) Set(v string) error {
+	name, cmd, ok := strings.Cut(v, "=")
+	if !ok {
+		cmd, name = v, ""
+	}
+	parts := strings.Fields(cmd)
+	if len(parts) == 0 {
+		return fmt.Errorf("commande vide pour %q", v)
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s#%d", parts[0], len(*f)+1)
+	}
+	*f = append(*f, engineSpec{Name: name, Path: parts[0], Args: parts[1:]})
+	return nil
+}
+
+// timeControl : temps fixe par coup, ou temps de base + incrément par coup
+type timeControl struct {
+	MoveTime time.Duration
+	Base     time.Duration
+	Inc      time.Duration
+}
+
+// parseTC lit "secondes+incrément" (ex : 10+0.1)
+func parseTC(s string) (timeControl, error) {
+	base, inc, _ := strings.Cut(s, "+")
+	b, err := strconv.ParseFloat(base, 64)
+	if err != nil {
+		return timeControl{}, fmt.Errorf("contrôle du temps invalide %q", s)
+	}
+	tc := timeControl{Base: time.Duration(b * float64(time.Second))}
+	if inc != "" {
+		i, err := strconv.ParseFloat(inc, 64)
+		if err != nil {
+			return timeControl{}, fmt.Errorf("incrément invalide %q", s)
+		}
+		tc.Inc = time.Duration(i * float64(time.Second))
+	}
+	return tc, nil
+}
+
+type config struct {
+	Rows, Cols, Win int
+	TC              timeControl
+	Grace           time.Duration
+	OpeningPlies    int
+}
+
+// job : une partie à jouer entre deux moteurs (red commence)
+type job struct {
+	Red, Yellow int
+	Opening     []int
+}
+
+// outcome : résultat d'une partie
+type outcome struct {
+	Job    job
+	Winner string // "R", "Y" ou "" (nulle)
+	Reason string
+	Moves  []int
+}
+
+func main() {
+	var engines engineFlags
+	flag.Var(&engines, "engine", "moteur nom=commande (à répéter)")
+	games := flag.Int("games", 20, "parties par paire de moteurs (arrondi au pair, couleurs alternées)")
+	rows := flag.Int("rows", 6, "lignes du plateau")
+	cols := flag.Int("cols", 7, "colonnes du plateau")
+	win := flag.Int("win", 4, "pions à aligner")
+	tcFlag := flag.String("tc", "", "contrôle du temps base+incrément en secondes (ex : 10+0.1)")
+	moveTime := flag.Duration("movetime", 100*time.Millisecond, "temps fixe par coup (si -tc absent)")
+	grace := flag.Duration("grace", 200*time.Millisecond, "marge de tolérance avant la perte au temps")
+	openingPlies := flag.Int("openingplies", 2, "nombre de coups d'ouverture aléatoires")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "graine des ouvertures")
+	concurrency := flag.Int("concurrency", 1, "parties jouées en parallèle")
+	sprtFlag := flag.String("sprt", "", "SPRT elo0,elo1 (arrêt anticipé avec deux moteurs)")
+	alpha := flag.Float64("alpha", 0.05, "risque alpha du SPRT")
+	beta := flag.Float64("beta", 0.05, "risque bêta du SPRT")
+	verbose := flag.Bool("v", false, "afficher chaque partie")
+	flag.Parse()
+
+	if len(engines) < 2 {
+		log.Fatal("il faut au moins deux moteurs (-engine nom=commande)")
+	}
+	cfg := config{Rows: *rows, Cols: *cols, Win: *win, Grace: *grace, OpeningPlies: *openingPlies}
+	cfg.TC.MoveTime = *moveTime
+	if *tcFlag != "" {
+		tc, err := parseTC(*tcFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.TC = tc
+	}
+	var sprt *SPRT
+	if *sprtFlag != "" {
+		var e0, e1 float64
+		if _, err := fmt.Sscanf(*sprtFlag, "%g,%g", &e0, &e1); err != nil {
+			log.Fatalf("SPRT invalide %q (attendu elo0,elo1)", *sprtFlag)
+		}
+		sprt = &SPRT{Elo0: e0, Elo1: e1, Alpha: *alpha, Beta: *beta}
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	a := &arena{cfg: cfg, engines: engines, stats: make(map[[2]int]*Stats)}
+
+	// Toutes rondes : chaque ouverture est jouée deux fois, couleurs inversées
+	var jobs []job
+	for i := 0; i < len(engines); i++ {
+		for j := i + 1; j < len(engines); j++ {
+			for g := 0; g < (*games+1)/2; g++ {
+				opening := randomOpening(rng, cfg)
+				jobs = append(jobs, job{Red: i, Yellow: j, Opening: opening}, job{Red: j, Yellow: i, Opening: opening})
+			}
+		}
+	}
+
+	queue := make(chan job)
+	results := make(chan outcome)
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				results <- a.play(j)
+			}
+		}()
+	}
+	stop := make(chan struct{})
+	go func() {
+		defer close(queue)
+		for _, j := range jobs {
+			select {
+			case queue <- j:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	decided := ""
+	for res := range results {
+		a.record(res)
+		if *verbose {
+			fmt.Printf("%s (R) - %s (Y) : %s %s [%d coups]\n", engines[res.Job.Red].Name, engines[res.Job.Yellow].Name,
+				resultText(res.Winner), res.Reason, len(res.Moves))
+		}
+		if sprt != nil && len(engines) == 2 && decided == "" {
+			if decided = sprt.Decision(*a.pair(0, 1)); decided != "" {
+				close(stop)
+			}
+		}
+	}
+
+	a.report(os.Stdout, sprt)
+	if decided != "" {
+		fmt.Printf("\nSPRT arrêté : %s acceptée\n", decided)
+	}
+}
+
+func resultText(winner string) string {
+	switch winner {
+	case "R":
+		return "1-0"
+	case "Y":
+		return "0-1"
+	}
+	return "½-½"
+}
+
+// randomOpening tire des coups au hasard sans terminer la partie
+func randomOpening(rng *rand.Rand, cfg config) []int {
+	for attempt := 0; attempt < 100; attempt++ {
+		st := game.GameState{Rows: cfg.Rows, Cols: cfg.Cols, WinLength: cfg.Win, Next: "R"}
+		var moves []int
+		ok := true
+		for i := 0; i < cfg.OpeningPlies; i++ {
+			legal := game.LegalColumns(st)
+			if len(legal) == 0 {
+				ok = false
+				break
+			}
+			c := legal[rng.Intn(len(legal))]
+			r := game.Drop(&st, c)
+			moves = append(moves, c)
+			if game.LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, c) {
+				ok = false
+				break
+			}
+			st.Next = game.Opponent(st.Next)
+		}
+		if ok {
+			return moves
+		}
+	}
+	return nil
+}
+
+type arena struct {
+	cfg     config
+	engines []engineSpec
+	mu      sync.Mutex
+	stats   map[[2]int]*Stats
+}
+
+// pair retourne les statistiques de i contre j. a.mu doit être verrouillé ou inutile.
+func (a *arena) pair(i, j int) *Stats {
+	k := [2]int{i, j}
+	if a.stats[k] == nil {
+		a.stats[k] = &Stats{}
+	}
+	return a.stats[k]
+}
+
+func (a *arena) record(res outcome) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	r, y := res.Job.Red, res.Job.Yellow
+	switch res.Winner {
+	case "R":
+		a.pair(r, y).Wins++
+		a.pair(y, r).Losses++
+	case "Y":
+		a.pair(r, y).Losses++
+		a.pair(y, r).Wins++
+	default:
+		a.pair(r, y).Draws++
+		a.pair(y, r).Draws++
+	}
+}
+
+// play joue une partie complète ; un moteur qui plante, dépasse son temps
+// ou joue un coup illégal perd la partie.
+func (a *arena) play(j job) outcome {
+	res := outcome{Job: j}
+	engines := map[string]*engine.Engine{}
+	for team, idx := range map[string]int{"R": j.Red, "Y": j.Yellow} {
+		spec := a.engines[idx]
+		e, err := engine.Start(spec.Path, spec.Args...)
+		if err != nil {
+			res.Winner, res.Reason = game.Opponent(team), fmt.Sprintf("%s ne démarre pas : %v", spec.Name, err)
+			for _, e := range engines {
+				e.Close()
+			}
+			return res
+		}
+		defer e.Close()
+		_ = e.NewGame()
+		engines[team] = e
+	}
+
+	cfg := a.cfg
+	st := game.GameState{Rows: cfg.Rows, Cols: cfg.Cols, WinLength: cfg.Win, Next: "R"}
+	for _, c := range j.Opening {
+		game.Drop(&st, c)
+		st.Next = game.Opponent(st.Next)
+	}
+	res.Moves = append(res.Moves, j.Opening...)
+	clock := engine.Clock{MoveTime: cfg.TC.MoveTime}
+	if cfg.TC.Base > 0 {
+		clock = engine.Clock{WTime: cfg.TC.Base, BTime: cfg.TC.Base, WInc: cfg.TC.Inc, BInc: cfg.TC.Inc}
+	}
+
+	for {
+		if len(game.LegalColumns(st)) == 0 {
+			res.Reason = "plateau plein"
+			return res
+		}
+		player := st.Next
+		start := time.Now()
+		col, err := engines[player].BestMove(engine.FormatStartPosition(cfg.Rows, cfg.Cols, cfg.Win, res.Moves), clock, player, cfg.Grace)
+		elapsed := time.Since(start)
+		if err != nil {
+			res.Winner, res.Reason = game.Opponent(player), fmt.Sprintf("%s : %v", player, err)
+			return res
+		}
+		if cfg.TC.Base > 0 {
+			left := &clock.WTime
+			if player == "Y" {
+				left = &clock.BTime
+			}
+			*left -= elapsed
+			if *left < -cfg.Grace {
+				res.Winner, res.Reason = game.Opponent(player), player+" : temps dépassé"
+				return res
+			}
+			if *left < 0 {
+				*left = 0
+			}
+			*left += cfg.TC.Inc
+		}
+		r := game.Drop(&st, col)
+		if r < 0 {
+			res.Winner, res.Reason = game.Opponent(player), fmt.Sprintf("%s : coup illégal %d", player, col)
+			return res
+		}
+		res.Moves = append(res.Moves, col)
+		if game.LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, col) {
+			res.Winner, res.Reason = player, "alignement"
+			return res
+		}
+		st.Next = game.Opponent(player)
+	}
+}
+
+// report affiche le classement, les confrontations et le SPRT
+func (a *arena) report(out *os.File, sprt *SPRT) {
+	type row struct {
+		Name  string
+		Total Stats
+	}
+	rows := make([]row, len(a.engines))
+	for i, e := range a.engines {
+		rows[i].Name = e.Name
+		for j := range a.engines {
+			if s, ok := a.stats[[2]int{i, j}]; ok && i != j {
+				rows[i].Total.Wins += s.Wins
+				rows[i].Total.Draws += s.Draws
+				rows[i].Total.Losses += s.Losses
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Total.Score() > rows[j].Total.Score() })
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Rang\tMoteur\tParties\tV\tN\tD\tScore\tElo")
+	for i, r := range rows {
+		elo, margin := r.Total.Elo()
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%d\t%d\t%.1f%%\t%+.0f ± %.0f\n", i+1, r.Name, r.Total.Games(),
+			r.Total.Wins, r.Total.Draws, r.Total.Losses, 100*r.Total.Score(), elo, margin)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(out, "\nConfrontations :")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i := range a.engines {
+		for j := i + 1; j < len(a.engines); j++ {
+			s := a.pair(i, j)
+			elo, margin := s.Elo()
+			line := fmt.Sprintf("%s vs %s\t+%d =%d -%d\t%+.0f ± %.0f", a.engines[i].Name, a.engines[j].Name,
+				s.Wins, s.Draws, s.Losses, elo, margin)
+			if sprt != nil {
+				lower, upper := sprt.Bounds()
+				line += fmt.Sprintf("\tLLR %.2f [%.2f, %.2f]", sprt.LLR(*s), lower, upper)
+			}
+			fmt.Fprintln(tw, line)
+		}
+	}
+	tw.Flush()
+}
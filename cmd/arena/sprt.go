@@ -0,0 +1,82 @@
+package main
+
+import "math"
+
+// Stats : résultats d'un moteur contre un autre (du point de vue du premier)
+type Stats struct {
+	Wins, Draws, Losses int
+}
+
+func (s Stats) Games() int { return s.Wins + s.Draws + s.Losses }
+
+// Score retourne la proportion de points marqués (victoire 1, nulle 0,5)
+func (s Stats) Score() float64 {
+	n := s.Games()
+	if n == 0 {
+		return 0.5
+	}
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(n)
+}
+
+// Elo estime l'écart Elo et la marge d'erreur à 95 %
+func (s Stats) Elo() (elo, margin float64) {
+	n := float64(s.Games())
+	if n == 0 {
+		return 0, 0
+	}
+	score := s.Score()
+	variance := (float64(s.Wins)*math.Pow(1-score, 2) +
+		float64(s.Draws)*math.Pow(0.5-score, 2) +
+		float64(s.Losses)*math.Pow(score, 2)) / n
+	dev := math.Sqrt(variance / n)
+	return scoreToElo(score), (scoreToElo(score+1.96*dev) - scoreToElo(score-1.96*dev)) / 2
+}
+
+func scoreToElo(s float64) float64 {
+	s = math.Min(math.Max(s, 1e-6), 1-1e-6)
+	return -400 * math.Log10(1/s-1)
+}
+
+func eloToScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// SPRT : test séquentiel du rapport de vraisemblance entre H0 (elo = Elo0) et H1 (elo = Elo1)
+type SPRT struct {
+	Elo0, Elo1  float64
+	Alpha, Beta float64
+}
+
+// Bounds retourne les bornes inférieure (accepter H0) et supérieure (accepter H1) du LLR
+func (t SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// LLR calcule le log du rapport de vraisemblance (approximation normale du modèle trinomial)
+func (t SPRT) LLR(s Stats) float64 {
+	n := float64(s.Games())
+	if n == 0 || s.Wins+s.Draws == 0 || s.Losses+s.Draws == 0 {
+		return 0
+	}
+	score := s.Score()
+	variance := (float64(s.Wins)*math.Pow(1-score, 2) +
+		float64(s.Draws)*math.Pow(0.5-score, 2) +
+		float64(s.Losses)*math.Pow(score, 2)) / n
+	if variance == 0 {
+		return 0
+	}
+	s0, s1 := eloToScore(t.Elo0), eloToScore(t.Elo1)
+	return (s1 - s0) * (2*score - s0 - s1) / (2 * variance / n)
+}
+
+// Decision retourne "H1", "H0" ou "" si le test doit continuer
+func (t SPRT) Decision(s Stats) string {
+	lower, upper := t.Bounds()
+	switch llr := t.LLR(s); {
+	case llr >= upper:
+		return "H1"
+	case llr <= lower:
+		return "H0"
+	}
+	return ""
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloSymmetric(t *testing.T) {
+	elo, _ := Stats{Wins: 60, Draws: 20, Losses: 20}.Elo()
+	opp, _ := Stats{Wins: 20, Draws: 20, Losses: 60}.Elo()
+	if math.Abs(elo+opp) > 1e-9 || elo <= 0 {
+		t.Fatalf("elo = %v / %v", elo, opp)
+	}
+}
+
+func TestSPRTDecides(t *testing.T) {
+	test := SPRT{Elo0: 0, Elo1: 20, Alpha: 0.05, Beta: 0.05}
+	if d := test.Decision(Stats{Wins: 600, Draws: 200, Losses: 200}); d != "H1" {
+		t.Fatalf("moteur nettement plus fort : décision %q", d)
+	}
+	if d := test.Decision(Stats{Wins: 200, Draws: 200, Losses: 600}); d != "H0" {
+		t.Fatalf("moteur nettement plus faible : décision %q", d)
+	}
+	if d := test.Decision(Stats{Wins: 3, Draws: 2, Losses: 3}); d != "" {
+		t.Fatalf("trop peu de parties : décision %q", d)
+	}
+}
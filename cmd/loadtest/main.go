@@ -0,0 +1,128 @@
+// Commande loadtest : simule N parties simultanées (deux clients WebSocket chacune)
+// contre un serveur Puissance 4 et mesure la latence de diffusion des états, les
+// erreurs, les connexions perdues et l'évolution de la mémoire du serveur.
+//
+// Exemple :
+//
+//	go run ./cmd/loadtest -parties 200 -mode multi-turbo -delay 100ms -duration 1m -pid $(pgrep power4)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "adresse du serveur")
+	n := flag.Int("parties", 10, "nombre de parties simultanées")
+	mode := flag.String("mode", "multi-classique", "mode des parties (multi-turbo pour tester les boosters)")
+	rows := flag.Int("rows", 6, "lignes du plateau")
+	cols := flag.Int("cols", 7, "colonnes du plateau")
+	delay := flag.Duration("delay", 200*time.Millisecond, "pause entre deux coups d'une partie")
+	aiRatio := flag.Float64("ai", 0, "proportion de coups joués par l'IA (0 à 1)")
+	aiThink := flag.Duration("think", 20*time.Millisecond, "temps de réflexion de l'IA")
+	boosterRatio := flag.Float64("boosters", 0.5, "probabilité d'utiliser un booster détenu (mode turbo)")
+	duration := flag.Duration("duration", 30*time.Second, "durée du test")
+	ramp := flag.Duration("ramp", 5*time.Second, "montée en charge : délai pour lancer toutes les parties")
+	stall := flag.Duration("stall", 10*time.Second, "délai sans nouvel état avant d'abandonner une partie")
+	pid := flag.Int("pid", 0, "PID du serveur local pour suivre sa mémoire (0 = non mesurée)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "graine aléatoire")
+	flag.Parse()
+
+	cfg := simConfig{
+		Server: *server, Mode: *mode, Rows: *rows, Cols: *cols,
+		MoveDelay: *delay, AIRatio: *aiRatio, AIThink: *aiThink, BoosterRatio: *boosterRatio, Stall: *stall,
+	}
+	stats := newStats()
+	log.Printf("Test de charge : %d parties (%s) pendant %v sur %s", *n, cfg, *duration, *server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	if *pid > 0 {
+		mem, err := residentMemory(*pid)
+		if err != nil {
+			log.Fatalf("mémoire du serveur : %v", err)
+		}
+		stats.MemoryStart, stats.MemoryPeak = mem, mem
+		go sampleMemory(ctx, *pid, stats)
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < *n; i++ {
+		if *n > 1 && *ramp > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(*ramp / time.Duration(*n)):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			runParty(ctx, cfg, stats, *seed+int64(i))
+		}(i)
+	}
+	go progress(ctx, stats, start)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if *pid > 0 {
+		if mem, err := residentMemory(*pid); err == nil {
+			stats.add(func(s *Stats) { s.MemoryEnd = mem })
+		}
+	}
+	fmt.Println()
+	stats.report(os.Stdout, elapsed)
+}
+
+// sampleMemory relève la mémoire du serveur chaque seconde pour en garder le pic
+func sampleMemory(ctx context.Context, pid int, stats *Stats) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			mem, err := residentMemory(pid)
+			if err != nil {
+				stats.errorf("mémoire")
+				continue
+			}
+			stats.add(func(s *Stats) {
+				if mem > s.MemoryPeak {
+					s.MemoryPeak = mem
+				}
+			})
+		}
+	}
+}
+
+// progress affiche un point d'étape toutes les cinq secondes
+func progress(ctx context.Context, stats *Stats, start time.Time) {
+	t := time.NewTicker(5 * time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			stats.add(func(s *Stats) {
+				log.Printf("%v : %d parties, %d coups, %d connexions perdues", time.Since(start).Round(time.Second),
+					s.Parties, s.Moves, s.Dropped)
+			})
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"power4/game"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// simConfig : paramètres communs à toutes les parties simulées
+type simConfig struct {
+	Server       string
+	Mode         string
+	Rows, Cols   int
+	MoveDelay    time.Duration // Pause entre deux coups d'une même partie
+	AIRatio      float64       // Proportion de coups choisis par l'IA (le reste au hasard)
+	AIThink      time.Duration
+	BoosterRatio float64 // Probabilité d'utiliser un booster détenu avant de jouer
+	Stall        time.Duration
+}
+
+// event : message reçu par l'un des deux clients d'une partie
+type event struct {
+	Team    string
+	Kind    string // "state", "error", "closed"
+	State   game.GameState
+	Blocked int
+}
+
+// simClient : un joueur connecté en WebSocket
+type simClient struct {
+	Team     string
+	Conn     *websocket.Conn
+	Boosters []string
+	writeMu  sync.Mutex
+}
+
+func (c *simClient) send(v interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.Conn.WriteJSON(v)
+}
+
+// pendingMove : coup envoyé dont on attend la diffusion
+type pendingMove struct {
+	Version int
+	SentAt  time.Time
+	Seen    map[string]bool
+}
+
+// simGame : une partie simulée, de la création à la fin
+type simGame struct {
+	cfg     simConfig
+	stats   *Stats
+	rng     *rand.Rand
+	code    string
+	clients map[string]*simClient
+	events  chan event
+	closing atomic.Bool
+	mu      sync.Mutex
+	pending *pendingMove
+}
+
+// runParty enchaîne des parties complètes jusqu'à l'annulation de ctx
+func runParty(ctx context.Context, cfg simConfig, stats *Stats, seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	for ctx.Err() == nil {
+		g := &simGame{cfg: cfg, stats: stats, rng: rng, clients: make(map[string]*simClient), events: make(chan event, 64)}
+		if err := g.play(ctx); err != nil {
+			stats.errorf(err.Error())
+			// Laisser respirer le serveur avant de réessayer
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+type simError string
+
+func (e simError) Error() string { return string(e) }
+
+const (
+	errCreate  = simError("création")
+	errDial    = simError("connexion")
+	errSend    = simError("envoi")
+	errStall   = simError("blocage")
+	errBooster = simError("booster")
+	errDropped = simError("coupure")
+)
+
+// play crée une partie, connecte les deux joueurs et joue jusqu'à la fin
+func (g *simGame) play(ctx context.Context) error {
+	base := strings.TrimRight(g.cfg.Server, "/")
+	q := url.Values{"mode": {g.cfg.Mode}, "rows": {strconv.Itoa(g.cfg.Rows)}, "cols": {strconv.Itoa(g.cfg.Cols)}}
+	resp, err := http.Get(base + "/api/party/create?" + q.Encode())
+	if err != nil {
+		return errCreate
+	}
+	var created map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || created["code"] == "" {
+		return errCreate
+	}
+	g.code = created["code"]
+	g.stats.add(func(s *Stats) { s.Parties++ })
+
+	wsBase := "ws" + strings.TrimPrefix(base, "http")
+	defer g.close()
+	for _, team := range []string{"R", "Y"} {
+		conn, _, err := websocket.DefaultDialer.Dial(wsBase+"/ws/"+g.code+"?team="+team, nil)
+		if err != nil {
+			return errDial
+		}
+		c := &simClient{Team: team, Conn: conn}
+		g.clients[team] = c
+		go g.read(c)
+	}
+
+	var st game.GameState
+	blocked := -1
+	moved := -1 // Version pour laquelle un coup a déjà été envoyé
+	stall := time.NewTimer(g.cfg.Stall)
+	defer stall.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stall.C:
+			return errStall
+		case ev := <-g.events:
+			switch ev.Kind {
+			case "closed":
+				return errDropped
+			case "error":
+				g.stats.add(func(s *Stats) { s.Refused++ })
+				moved = -1 // Coup refusé (colonne bloquée...) : rejouer
+			case "state":
+				if ev.Blocked >= -1 {
+					blocked = ev.Blocked
+				}
+				if ev.State.Version < st.Version || (ev.State.Version == st.Version && moved == st.Version) {
+					continue
+				}
+				st = ev.State
+			}
+		}
+		if st.Finished {
+			g.stats.add(func(s *Stats) { s.Games++ })
+			return nil
+		}
+		if moved == st.Version || st.Next == "" {
+			continue
+		}
+		if !stall.Stop() {
+			select {
+			case <-stall.C:
+			default:
+			}
+		}
+		stall.Reset(g.cfg.Stall)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(g.cfg.MoveDelay):
+		}
+		c := g.clients[st.Next]
+		if kind, ok := g.maybeBooster(c, st); ok {
+			if kind != "double-shot" {
+				moved = st.Version // Attendre l'état mis à jour par le booster
+				continue
+			}
+		} else if kind != "" {
+			return errBooster
+		}
+		col := g.chooseColumn(st, blocked)
+		if col < 0 {
+			continue
+		}
+		g.mu.Lock()
+		g.pending = &pendingMove{Version: st.Version, SentAt: time.Now(), Seen: map[string]bool{}}
+		g.mu.Unlock()
+		if err := c.send(map[string]interface{}{"type": "play", "col": col}); err != nil {
+			return errSend
+		}
+		g.stats.add(func(s *Stats) { s.Moves++ })
+		moved = st.Version
+	}
+}
+
+// read transmet les messages d'un client à la boucle de jeu et mesure la latence de diffusion
+func (g *simGame) read(c *simClient) {
+	for {
+		_, data, err := c.Conn.ReadMessage()
+		if err != nil {
+			if !g.closing.Load() {
+				g.stats.add(func(s *Stats) { s.Dropped++ })
+				g.events <- event{Team: c.Team, Kind: "closed"}
+			}
+			return
+		}
+		var msg struct {
+			Type    string          `json:"type"`
+			State   *game.GameState `json:"state"`
+			Blocked *int            `json:"blocked"`
+			Booster string          `json:"booster"`
+		}
+		if json.Unmarshal(data, &msg) != nil {
+			continue
+		}
+		ev := event{Team: c.Team, Kind: msg.Type, Blocked: -2}
+		switch msg.Type {
+		case "":
+			// Premier message : l'état brut
+			if json.Unmarshal(data, &ev.State) != nil {
+				continue
+			}
+			ev.Kind = "state"
+		case "state":
+			if msg.State == nil {
+				continue
+			}
+			ev.State = *msg.State
+			if msg.Blocked != nil {
+				ev.Blocked = *msg.Blocked
+			}
+			if msg.Booster != "" {
+				g.mu.Lock()
+				c.Boosters = append(c.Boosters, msg.Booster)
+				g.mu.Unlock()
+			}
+			g.observe(c.Team, ev.State.Version)
+		case "error":
+		default:
+			continue
+		}
+		select {
+		case g.events <- ev:
+		default:
+			// Boucle de jeu en retard : l'état suivant contiendra ce changement
+		}
+	}
+}
+
+// observe enregistre la latence quand un client reçoit l'état suivant le coup en attente
+func (g *simGame) observe(team string, version int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p := g.pending
+	if p == nil || version <= p.Version || p.Seen[team] {
+		return
+	}
+	p.Seen[team] = true
+	d := time.Since(p.SentAt)
+	g.stats.add(func(s *Stats) { s.Latencies = append(s.Latencies, d) })
+}
+
+func (g *simGame) close() {
+	g.closing.Store(true)
+	for _, c := range g.clients {
+		_ = c.Conn.Close()
+	}
+}
+
+// chooseColumn tire une colonne jouable au hasard ou demande un coup à l'IA
+func (g *simGame) chooseColumn(st game.GameState, blocked int) int {
+	if g.rng.Float64() < g.cfg.AIRatio {
+		if col := game.BestMove(st, g.cfg.AIThink); col != blocked {
+			return col
+		}
+	}
+	var legal []int
+	for _, c := range game.LegalColumns(st) {
+		if c != blocked {
+			legal = append(legal, c)
+		}
+	}
+	if len(legal) == 0 {
+		return blocked // Seule colonne restante : le serveur la débloquera
+	}
+	return legal[g.rng.Intn(len(legal))]
+}
+
+// maybeBooster utilise parfois un booster détenu par c. Retourne le type utilisé et
+// ok = false en cas d'échec de l'appel (type vide si aucun booster n'a été tenté).
+func (g *simGame) maybeBooster(c *simClient, st game.GameState) (string, bool) {
+	g.mu.Lock()
+	if len(c.Boosters) == 0 || g.rng.Float64() >= g.cfg.BoosterRatio {
+		g.mu.Unlock()
+		return "", false
+	}
+	kind := c.Boosters[0]
+	c.Boosters = c.Boosters[1:]
+	g.mu.Unlock()
+
+	var filled, empty [][2]int
+	for r := 0; r < st.Rows; r++ {
+		for col := 0; col < st.Cols; col++ {
+			if st.Board[r][col] == "" {
+				empty = append(empty, [2]int{r, col})
+			} else {
+				filled = append(filled, [2]int{r, col})
+			}
+		}
+	}
+	pick := func(cells [][2]int) [2]int {
+		if len(cells) == 0 {
+			return [2]int{0, 0}
+		}
+		return cells[g.rng.Intn(len(cells))]
+	}
+	form := url.Values{"action": {kind}, "player": {c.Team}, "code": {g.code}}
+	switch kind {
+	case "remove-piece":
+		cell := pick(filled)
+		form.Set("row", strconv.Itoa(cell[0]))
+		form.Set("col", strconv.Itoa(cell[1]))
+	case "block-column":
+		form.Set("col", strconv.Itoa(g.rng.Intn(st.Cols)))
+	case "swap-colors":
+		a, b := pick(filled), pick(filled)
+		form.Set("row1", strconv.Itoa(a[0]))
+		form.Set("col1", strconv.Itoa(a[1]))
+		form.Set("row2", strconv.Itoa(b[0]))
+		form.Set("col2", strconv.Itoa(b[1]))
+	case "wildcard":
+		cell := pick(empty)
+		form.Set("row", strconv.Itoa(cell[0]))
+		form.Set("col", strconv.Itoa(cell[1]))
+	}
+
+	start := time.Now()
+	resp, err := http.PostForm(strings.TrimRight(g.cfg.Server, "/")+"/booster-action", form)
+	if err != nil {
+		return kind, false
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&result) != nil || !result.Success {
+		return kind, false
+	}
+	d := time.Since(start)
+	g.stats.add(func(s *Stats) {
+		s.BoosterRTT = append(s.BoosterRTT, d)
+		s.Boosters[kind]++
+	})
+	return kind, true
+}
+
+func (c simConfig) String() string {
+	return fmt.Sprintf("%s %dx%d, un coup toutes les %v, IA %.0f%%, boosters %.0f%%",
+		c.Mode, c.Rows, c.Cols, c.MoveDelay, 100*c.AIRatio, 100*c.BoosterRatio)
+}
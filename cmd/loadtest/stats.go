@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stats : compteurs partagés par toutes les parties simulées
+type Stats struct {
+	mu          sync.Mutex
+	Latencies   []time.Duration // Délai entre l'envoi d'un coup et la réception de l'état par chaque client
+	BoosterRTT  []time.Duration // Durée des appels /booster-action
+	Parties     int
+	Games       int // Parties menées à leur terme
+	Moves       int
+	Boosters    map[string]int // Boosters utilisés par type
+	Refused     int            // Coups refusés par le serveur (message "error")
+	Errors      map[string]int // Erreurs par catégorie
+	Dropped     int            // Connexions WebSocket coupées par le serveur
+	MemoryStart int64          // Mémoire résidente du serveur (octets), 0 si non mesurée
+	MemoryPeak  int64
+	MemoryEnd   int64
+}
+
+func newStats() *Stats {
+	return &Stats{Boosters: make(map[string]int), Errors: make(map[string]int)}
+}
+
+func (s *Stats) add(f func(s *Stats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f(s)
+}
+
+func (s *Stats) errorf(kind string) {
+	s.add(func(s *Stats) { s.Errors[kind]++ })
+}
+
+// percentile retourne le p-ième centile (0-100) de durées déjà triées
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p/100*float64(len(sorted)) + 0.5)
+	if idx < 1 {
+		idx = 1
+	}
+	if idx > len(sorted) {
+		idx = len(sorted)
+	}
+	return sorted[idx-1]
+}
+
+// distribution formate min, centiles et max d'une série de durées
+func distribution(d []time.Duration) string {
+	if len(d) == 0 {
+		return "aucune mesure"
+	}
+	sorted := append([]time.Duration(nil), d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	r := func(v time.Duration) time.Duration { return v.Round(10 * time.Microsecond) }
+	return fmt.Sprintf("n=%d p50=%v p90=%v p99=%v max=%v", len(sorted),
+		r(percentile(sorted, 50)), r(percentile(sorted, 90)), r(percentile(sorted, 99)), r(sorted[len(sorted)-1]))
+}
+
+func formatBytes(b int64) string {
+	if b == 0 {
+		return "non mesurée"
+	}
+	return fmt.Sprintf("%.1f Mo", float64(b)/(1<<20))
+}
+
+// report affiche le bilan du test de charge
+func (s *Stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(w, "Durée            : %v\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Parties créées   : %d (%d terminées)\n", s.Parties, s.Games)
+	fmt.Fprintf(w, "Coups joués      : %d (%.1f/s), %d refusés\n", s.Moves, float64(s.Moves)/elapsed.Seconds(), s.Refused)
+	fmt.Fprintf(w, "Diffusion état   : %s\n", distribution(s.Latencies))
+	if len(s.BoosterRTT) > 0 {
+		var parts []string
+		for _, k := range sortedKeys(s.Boosters) {
+			parts = append(parts, k+"="+strconv.Itoa(s.Boosters[k]))
+		}
+		fmt.Fprintf(w, "Boosters         : %s\n", strings.Join(parts, " "))
+		fmt.Fprintf(w, "Appels booster   : %s\n", distribution(s.BoosterRTT))
+	}
+	fmt.Fprintf(w, "Connexions perdues : %d\n", s.Dropped)
+	if len(s.Errors) == 0 {
+		fmt.Fprintln(w, "Erreurs          : aucune")
+	}
+	for _, k := range sortedKeys(s.Errors) {
+		fmt.Fprintf(w, "Erreurs %-8s : %d\n", k, s.Errors[k])
+	}
+	if s.MemoryStart > 0 {
+		fmt.Fprintf(w, "Mémoire serveur  : %s → %s (pic %s, %+.1f Mo)\n", formatBytes(s.MemoryStart),
+			formatBytes(s.MemoryEnd), formatBytes(s.MemoryPeak), float64(s.MemoryEnd-s.MemoryStart)/(1<<20))
+	} else {
+		fmt.Fprintln(w, "Mémoire serveur  : non mesurée (utiliser -pid)")
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// residentMemory lit la mémoire résidente (VmRSS) d'un processus local via /proc
+func residentMemory(pid int) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+			kb, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(v), " kB"), 10, 64)
+			return kb * 1024, err
+		}
+	}
+	return 0, fmt.Errorf("VmRSS absent pour le processus %d", pid)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var d []time.Duration
+	for i := 1; i <= 100; i++ {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	cases := map[float64]time.Duration{0: time.Millisecond, 50: 50 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond}
+	for p, want := range cases {
+		if got := percentile(d, p); got != want {
+			t.Fatalf("p%v = %v, attendu %v", p, got, want)
+		}
+	}
+	if percentile(nil, 50) != 0 {
+		t.Fatal("centile d'une série vide")
+	}
+}
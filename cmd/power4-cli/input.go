@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// command : une ligne saisie par le joueur, coordonnées converties en base 0
+type command struct {
+	Kind    string // "play", "pop", "booster", "chat", "help", "quit"
+	Col     int
+	Booster string
+	Args    []int // Coordonnées du booster (ligne, colonne...)
+	Text    string
+}
+
+var errUnknownCommand = errors.New("commande inconnue (tapez h pour l'aide)")
+
+const helpText = `Commandes :
+  <colonne>                 jouer dans la colonne (1 = la plus à gauche)
+  p <colonne>               PopOut : retirer votre pion du bas de la colonne
+  b double                  booster double coup
+  b remove <ligne> <col>    retirer un pion
+  b block <col>             bloquer une colonne pour l'adversaire
+  b swap <l1> <c1> <l2> <c2> échanger deux pions
+  b joker <ligne> <col>     placer un pion n'importe où
+  c <message>               envoyer un message dans le chat
+  h                         afficher cette aide
+  q                         quitter`
+
+// boosterArgs : nom de commande -> (action du serveur, nombre de coordonnées)
+var boosterArgs = map[string]struct {
+	Action string
+	Count  int
+}{
+	"double": {"double-shot", 0},
+	"remove": {"remove-piece", 2},
+	"block":  {"block-column", 1},
+	"swap":   {"swap-colors", 4},
+	"joker":  {"wildcard", 2},
+}
+
+// parseCommand interprète une ligne saisie au clavier
+func parseCommand(line string) (command, error) {
+	line = strings.TrimSpace(line)
+	f := strings.Fields(line)
+	if len(f) == 0 {
+		return command{}, errUnknownCommand
+	}
+	switch f[0] {
+	case "q", "quit":
+		return command{Kind: "quit"}, nil
+	case "h", "help", "?":
+		return command{Kind: "help"}, nil
+	case "c", "chat":
+		text := strings.TrimSpace(strings.TrimPrefix(line, f[0]))
+		if text == "" {
+			return command{}, errors.New("message vide")
+		}
+		return command{Kind: "chat", Text: text}, nil
+	case "p", "pop":
+		if len(f) != 2 {
+			return command{}, errors.New("colonne manquante")
+		}
+		n, err := strconv.Atoi(f[1])
+		if err != nil || n < 1 {
+			return command{}, errors.New("colonne invalide : " + f[1])
+		}
+		return command{Kind: "pop", Col: n - 1}, nil
+	case "b", "booster":
+		if len(f) < 2 {
+			return command{}, errors.New("booster manquant")
+		}
+		spec, ok := boosterArgs[f[1]]
+		if !ok {
+			return command{}, errors.New("booster inconnu : " + f[1])
+		}
+		if len(f)-2 != spec.Count {
+			return command{}, errors.New("nombre de coordonnées incorrect pour " + f[1])
+		}
+		cmd := command{Kind: "booster", Booster: spec.Action}
+		for _, a := range f[2:] {
+			n, err := strconv.Atoi(a)
+			if err != nil || n < 1 {
+				return command{}, errors.New("coordonnée invalide : " + a)
+			}
+			cmd.Args = append(cmd.Args, n-1)
+		}
+		return cmd, nil
+	}
+	n, err := strconv.Atoi(f[0])
+	if err != nil || len(f) != 1 || n < 1 {
+		return command{}, errUnknownCommand
+	}
+	return command{Kind: "play", Col: n - 1}, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	cases := map[string]command{
+		"3":              {Kind: "play", Col: 2},
+		" 15 ":           {Kind: "play", Col: 14},
+		"p 4":            {Kind: "pop", Col: 3},
+		"b double":       {Kind: "booster", Booster: "double-shot"},
+		"b block 7":      {Kind: "booster", Booster: "block-column", Args: []int{6}},
+		"b swap 1 2 3 4": {Kind: "booster", Booster: "swap-colors", Args: []int{0, 1, 2, 3}},
+		"c bien joué !":  {Kind: "chat", Text: "bien joué !"},
+		"q":              {Kind: "quit"},
+	}
+	for line, want := range cases {
+		got, err := parseCommand(line)
+		if err != nil {
+			t.Fatalf("%q : %v", line, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%q : %+v, attendu %+v", line, got, want)
+		}
+	}
+	for _, line := range []string{"", "0", "x", "b", "b remove 1", "b fly", "c", "3 4", "p", "p 0"} {
+		if _, err := parseCommand(line); err == nil {
+			t.Fatalf("%q aurait dû être refusé", line)
+		}
+	}
+}
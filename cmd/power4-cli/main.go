@@ -0,0 +1,64 @@
+// Commande power4-cli : client terminal pour créer, rejoindre ou regarder une partie
+// sur un serveur Puissance 4, ou jouer hors ligne contre l'IA locale.
+//
+// Exemples :
+//
+//	go run ./cmd/power4-cli -mode multi-turbo               # crée une partie et affiche son code
+//	go run ./cmd/power4-cli -join ABC123                    # rejoint la partie en Jaune
+//	go run ./cmd/power4-cli -join ABC123 -watch             # regarde la partie
+//	go run ./cmd/power4-cli -offline -rows 8 -cols 9        # joue contre l'IA sans serveur
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	server := flag.String("server", "http://localhost:8080", "adresse du serveur")
+	mode := flag.String("mode", "multi-classique", "mode de la partie créée (multi-classique, multi-turbo, multi-exponentiel...)")
+	rows := flag.Int("rows", 6, "lignes du plateau (4 à 15)")
+	cols := flag.Int("cols", 7, "colonnes du plateau (4 à 15)")
+	win := flag.Int("win", 4, "pions à aligner (hors ligne)")
+	join := flag.String("join", "", "code de la partie à rejoindre")
+	team := flag.String("team", "", "équipe R ou Y (par défaut R à la création, Y en rejoignant)")
+	player := flag.String("player", os.Getenv("USER"), "nom du joueur (parties par correspondance)")
+	watch := flag.Bool("watch", false, "regarder la partie -join sans jouer")
+	offline := flag.Bool("offline", false, "jouer hors ligne contre l'IA locale")
+	think := flag.Duration("think", time.Second, "temps de réflexion de l'IA hors ligne")
+	noColor := flag.Bool("nocolor", false, "désactiver les couleurs")
+	flag.Parse()
+
+	if *noColor || os.Getenv("NO_COLOR") != "" {
+		disableColors()
+	}
+	if *rows < 4 || *rows > 15 || *cols < 4 || *cols > 15 {
+		fmt.Fprintln(os.Stderr, "le plateau doit faire entre 4x4 et 15x15")
+		os.Exit(2)
+	}
+	*team = strings.ToUpper(*team)
+	if *team != "" && *team != "R" && *team != "Y" {
+		fmt.Fprintln(os.Stderr, "équipe invalide (R ou Y)")
+		os.Exit(2)
+	}
+
+	var err error
+	if *offline {
+		if *team == "" {
+			*team = "R"
+		}
+		err = runOffline(*rows, *cols, *win, *team, *think, os.Stdin, os.Stdout)
+	} else {
+		err = runOnline(onlineOptions{
+			Server: *server, Mode: *mode, Rows: *rows, Cols: *cols,
+			Join: *join, Team: *team, Player: *player, Watch: *watch,
+		}, os.Stdin, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"power4/game"
+	"time"
+)
+
+// runOffline joue une partie hors ligne contre l'IA locale, sans serveur.
+// Les boosters n'existent qu'en ligne : seules les règles classiques s'appliquent ici.
+func runOffline(rows, cols, win int, team string, think time.Duration, in io.Reader, out io.Writer) error {
+	st := game.GameState{Rows: rows, Cols: cols, WinLength: win, Next: "R", Mode: "solo-classique"}
+	sc := bufio.NewScanner(in)
+	fmt.Fprintf(out, "Partie hors ligne contre l'IA (%dx%d, %d pions à aligner). Vous jouez %s.\n", rows, cols, win, teamName(team))
+
+	for !st.Finished {
+		col := -1
+		if st.Next == team {
+			fmt.Fprintln(out)
+			render(out, st, -1)
+			fmt.Fprintln(out, status(st, team))
+			if !sc.Scan() {
+				return sc.Err()
+			}
+			cmd, err := parseCommand(sc.Text())
+			if err != nil {
+				fmt.Fprintf(out, "⚠️  %v\n", err)
+				continue
+			}
+			switch cmd.Kind {
+			case "quit":
+				return nil
+			case "help":
+				fmt.Fprintln(out, helpText)
+				continue
+			case "play":
+				col = cmd.Col
+			default:
+				fmt.Fprintln(out, "⚠️  Commande disponible uniquement en ligne")
+				continue
+			}
+		} else {
+			start := time.Now()
+			col = game.BestMove(st, think)
+			fmt.Fprintf(out, "🤖 L'IA joue la colonne %d (%s)\n", col+1, time.Since(start).Round(time.Millisecond))
+		}
+
+		if col >= st.Cols {
+			fmt.Fprintln(out, "⚠️  Colonne inexistante")
+			continue
+		}
+		r := game.Drop(&st, col)
+		if r < 0 {
+			fmt.Fprintln(out, "⚠️  Colonne pleine")
+			continue
+		}
+		switch {
+		case game.LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, col):
+			st.Winner, st.Finished = st.Next, true
+		case len(game.LegalColumns(st)) == 0:
+			st.Finished = true
+		default:
+			st.Next = game.Opponent(st.Next)
+		}
+	}
+	fmt.Fprintln(out)
+	render(out, st, -1)
+	fmt.Fprintln(out, status(st, team))
+	return nil
+}
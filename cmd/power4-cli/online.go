@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"power4/game"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// onlineOptions : paramètres d'une partie jouée contre le serveur
+type onlineOptions struct {
+	Server string // URL de base, ex : http://localhost:8080
+	Mode   string
+	Rows   int
+	Cols   int
+	Join   string // Code de la partie à rejoindre (vide = en créer une)
+	Team   string
+	Player string
+	Watch  bool
+}
+
+// wsMessage : messages reçus du serveur sur /ws/{code}
+type wsMessage struct {
+	Type    string          `json:"type"`
+	State   *game.GameState `json:"state"`
+	Blocked *int            `json:"blocked"`
+	Booster string          `json:"booster"`
+	Message string          `json:"message"`
+	Team    string          `json:"team"`
+	Name    string          `json:"name"`
+	Text    string          `json:"text"`
+	Status  string          `json:"status"`
+}
+
+type onlineClient struct {
+	opts     onlineOptions
+	out      io.Writer
+	code     string
+	team     string
+	token    string
+	conn     *websocket.Conn
+	mu       sync.Mutex
+	st       game.GameState
+	blocked  int
+	boosters []string // Boosters récupérés et pas encore utilisés
+}
+
+// getJSON appelle une route de l'API et décode la réponse JSON
+func getJSON(u string, v interface{}) error {
+	resp, err := http.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s : %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func runOnline(opts onlineOptions, in io.Reader, out io.Writer) error {
+	c := &onlineClient{opts: opts, out: out, blocked: -1, team: opts.Team}
+	base := strings.TrimRight(opts.Server, "/")
+	var resp map[string]string
+
+	switch {
+	case opts.Join == "":
+		q := url.Values{"mode": {opts.Mode}, "rows": {strconv.Itoa(opts.Rows)}, "cols": {strconv.Itoa(opts.Cols)}}
+		if err := getJSON(base+"/api/party/create?"+q.Encode(), &resp); err != nil {
+			return fmt.Errorf("création de la partie : %w", err)
+		}
+		if c.team == "" {
+			c.team = "R"
+		}
+		fmt.Fprintf(out, "Partie créée : %s%s%s (à partager avec votre adversaire)\n", colorBold, resp["code"], colorReset)
+	case opts.Watch:
+		resp = map[string]string{"code": strings.ToUpper(opts.Join)}
+		c.team = "S"
+	default:
+		q := url.Values{"code": {strings.ToUpper(opts.Join)}, "team": {c.team}, "player": {opts.Player}}
+		if err := getJSON(base+"/api/party/join?"+q.Encode(), &resp); err != nil {
+			return fmt.Errorf("impossible de rejoindre %s : %w", opts.Join, err)
+		}
+		if c.team == "" {
+			c.team = "Y"
+		}
+	}
+	c.code = resp["code"]
+	if resp["team"] != "" {
+		c.team = resp["team"]
+	}
+	c.token = resp["token"]
+
+	wsURL, err := url.Parse(base + "/ws/" + c.code)
+	if err != nil {
+		return err
+	}
+	wsURL.Scheme = strings.Replace(wsURL.Scheme, "http", "ws", 1)
+	wsURL.RawQuery = url.Values{"team": {c.team}, "token": {c.token}}.Encode()
+	c.conn, _, err = websocket.DefaultDialer.Dial(wsURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("connexion WebSocket : %w", err)
+	}
+	defer c.conn.Close()
+	fmt.Fprintf(out, "Connecté à la partie %s en tant que %s. Tapez h pour l'aide.\n", c.code, teamName(c.team))
+
+	done := make(chan error, 1)
+	go func() { done <- c.readLoop() }()
+	go func() { done <- c.inputLoop(in) }()
+	return <-done
+}
+
+// readLoop affiche chaque message reçu du serveur
+func (c *onlineClient) readLoop() error {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) && closeErr.Text != "" {
+				return errors.New(closeErr.Text) // Fermeture expliquée par le serveur
+			}
+			return errors.New("connexion au serveur perdue")
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.Type == "" {
+			// Premier message : l'état brut de la partie
+			var st game.GameState
+			if json.Unmarshal(data, &st) == nil {
+				msg.Type, msg.State = "state", &st
+			}
+		}
+
+		c.mu.Lock()
+		switch msg.Type {
+		case "state":
+			if msg.State != nil {
+				c.st = *msg.State
+			}
+			if msg.Blocked != nil {
+				c.blocked = *msg.Blocked
+			}
+			if msg.Booster != "" {
+				c.boosters = append(c.boosters, msg.Booster)
+				fmt.Fprintf(c.out, "🎁 Booster récupéré : %s\n", boosterNames[msg.Booster])
+			}
+			c.draw()
+		case "error", "chat-error":
+			fmt.Fprintf(c.out, "⚠️  %s\n", msg.Message)
+		case "chat":
+			fmt.Fprintf(c.out, "💬 %s : %s\n", msg.Name, msg.Text)
+		case "reaction":
+			fmt.Fprintf(c.out, "%s %s\n", msg.Name, msg.Text)
+		case "announcement":
+			fmt.Fprintf(c.out, "📢 %s\n", msg.Text)
+		case "rematch":
+			fmt.Fprintf(c.out, "🔁 Revanche : %s par %s\n", msg.Status, teamName(msg.Team))
+		}
+		c.mu.Unlock()
+	}
+}
+
+// draw affiche le plateau et l'inventaire. c.mu doit être verrouillé.
+func (c *onlineClient) draw() {
+	fmt.Fprintln(c.out)
+	render(c.out, c.st, c.blocked)
+	if len(c.boosters) > 0 {
+		names := make([]string, len(c.boosters))
+		for i, b := range c.boosters {
+			names[i] = boosterNames[b]
+		}
+		fmt.Fprintf(c.out, "Vos boosters : %s\n", strings.Join(names, ", "))
+	}
+	fmt.Fprintln(c.out, status(c.st, c.team))
+}
+
+// inputLoop lit les commandes du joueur et les transmet au serveur
+func (c *onlineClient) inputLoop(in io.Reader) error {
+	sc := bufio.NewScanner(in)
+	for sc.Scan() {
+		cmd, err := parseCommand(sc.Text())
+		if err != nil {
+			fmt.Fprintf(c.out, "⚠️  %v\n", err)
+			continue
+		}
+		switch cmd.Kind {
+		case "quit":
+			return nil
+		case "help":
+			fmt.Fprintln(c.out, helpText)
+		case "chat":
+			err = c.conn.WriteJSON(map[string]interface{}{"type": "chat", "text": cmd.Text})
+		case "play", "pop":
+			if c.team == "S" {
+				err = errors.New("les spectateurs ne peuvent pas jouer")
+				break
+			}
+			err = c.conn.WriteJSON(map[string]interface{}{"type": cmd.Kind, "col": cmd.Col})
+		case "booster":
+			err = c.useBooster(cmd)
+		}
+		if err != nil {
+			fmt.Fprintf(c.out, "⚠️  %v\n", err)
+		}
+	}
+	return sc.Err()
+}
+
+// useBooster consomme un booster de l'inventaire et l'applique via /booster-action
+func (c *onlineClient) useBooster(cmd command) error {
+	c.mu.Lock()
+	idx := -1
+	for i, b := range c.boosters {
+		if b == cmd.Booster {
+			idx = i
+			break
+		}
+	}
+	c.mu.Unlock()
+	if idx < 0 {
+		return fmt.Errorf("vous n'avez pas le booster %s", boosterNames[cmd.Booster])
+	}
+
+	form := url.Values{"action": {cmd.Booster}, "player": {c.team}, "code": {c.code}, "token": {c.token}}
+	keys := map[string][]string{
+		"remove-piece": {"row", "col"},
+		"block-column": {"col"},
+		"swap-colors":  {"row1", "col1", "row2", "col2"},
+		"wildcard":     {"row", "col"},
+	}[cmd.Booster]
+	for i, k := range keys {
+		form.Set(k, strconv.Itoa(cmd.Args[i]))
+	}
+	resp, err := http.PostForm(strings.TrimRight(c.opts.Server, "/")+"/booster-action", form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if !result.Success {
+		return errors.New(result.Message)
+	}
+
+	c.mu.Lock()
+	for i, b := range c.boosters {
+		if b == cmd.Booster {
+			c.boosters = append(c.boosters[:i], c.boosters[i+1:]...)
+			break
+		}
+	}
+	c.mu.Unlock()
+	fmt.Fprintf(c.out, "✨ %s\n", result.Message)
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"power4/game"
+	"strings"
+)
+
+// Codes ANSI utilisés pour l'affichage (désactivés avec -nocolor)
+var (
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorDim    = "\x1b[2m"
+	colorBold   = "\x1b[1m"
+	colorReset  = "\x1b[0m"
+)
+
+func disableColors() {
+	colorRed, colorYellow, colorDim, colorBold, colorReset = "", "", "", "", ""
+}
+
+// boosterSymbols : lettre affichée sur une case booster vide
+var boosterSymbols = map[string]string{
+	"double-shot":  "D",
+	"remove-piece": "X",
+	"block-column": "B",
+	"swap-colors":  "S",
+	"wildcard":     "J",
+}
+
+// boosterNames : noms affichés dans la légende et l'inventaire
+var boosterNames = map[string]string{
+	"double-shot":  "double coup",
+	"remove-piece": "retirer un pion",
+	"block-column": "bloquer une colonne",
+	"swap-colors":  "échanger deux pions",
+	"wildcard":     "joker",
+}
+
+func teamName(team string) string {
+	switch team {
+	case "R":
+		return colorRed + "Rouge" + colorReset
+	case "Y":
+		return colorYellow + "Jaune" + colorReset
+	}
+	return "Spectateur"
+}
+
+func disc(team string) string {
+	if colorReset == "" {
+		return team // Sans couleurs, les pions sont représentés par leur lettre
+	}
+	switch team {
+	case "R":
+		return colorRed + "●" + colorReset
+	case "Y":
+		return colorYellow + "●" + colorReset
+	}
+	return "?"
+}
+
+// render dessine le plateau (colonnes numérotées à partir de 1, lignes aussi pour
+// les boosters qui visent une case). blocked vaut -1 si aucune colonne n'est bloquée.
+func render(w io.Writer, st game.GameState, blocked int) {
+	var b strings.Builder
+	b.WriteString("    ")
+	for c := 0; c < st.Cols; c++ {
+		if c == blocked {
+			fmt.Fprintf(&b, "%s%2s%s ", colorDim, "##", colorReset)
+		} else {
+			fmt.Fprintf(&b, "%2d ", c+1)
+		}
+	}
+	b.WriteString("\n")
+	for r := 0; r < st.Rows; r++ {
+		fmt.Fprintf(&b, "%2d |", r+1)
+		for c := 0; c < st.Cols; c++ {
+			cell := st.Board[r][c]
+			switch {
+			case cell != "":
+				b.WriteString(" " + disc(cell) + " ")
+			case st.BoosterCells[r][c] != "":
+				b.WriteString(" " + colorDim + boosterSymbols[st.BoosterCells[r][c]] + colorReset + " ")
+			default:
+				b.WriteString(" · ")
+			}
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString("   +" + strings.Repeat("---", st.Cols) + "+\n")
+	if strings.Contains(st.Mode, "turbo") {
+		b.WriteString(colorDim + "   Boosters : D double coup, X retirer, B bloquer, S échanger, J joker" + colorReset + "\n")
+	}
+	fmt.Fprint(w, b.String())
+}
+
+// status décrit la situation de la partie du point de vue de team
+func status(st game.GameState, team string) string {
+	switch {
+	case st.Finished && st.Winner == "":
+		return colorBold + "Match nul !" + colorReset
+	case st.Finished:
+		return colorBold + "Victoire de " + teamName(st.Winner) + colorBold + " !" + colorReset
+	case team == st.Next:
+		return "À vous de jouer (" + teamName(team) + ")"
+	}
+	return "Au tour de " + teamName(st.Next)
+}
@@ -0,0 +1,28 @@
+// Commande power4-engine : moteur de référence parlant le protocole p4e (voir package engine)
+// sur stdin/stdout, basé sur l'IA locale du package game.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"power4/engine"
+	"power4/game"
+	"time"
+)
+
+func main() {
+	depth := flag.Int("depth", 0, "profondeur maximale de recherche (0 = limitée par le temps)")
+	name := flag.String("name", "power4-ai", "nom annoncé par le moteur")
+	flag.Parse()
+
+	choose := func(st game.GameState, budget time.Duration) int {
+		move, score, d := game.Search(st, budget*9/10, *depth)
+		fmt.Fprintf(os.Stdout, "info depth %d score %d\n", d, score)
+		return move
+	}
+	if err := engine.Serve(os.Stdin, os.Stdout, *name, "puissance2x2", choose); err != nil {
+		log.Fatal(err)
+	}
+}
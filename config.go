@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"power4/game"
+	"strings"
+	"time"
+)
+
+// ---------------- CONFIGURATION ----------------
+// Valeurs par défaut, surchargées dans l'ordre par un fichier JSON (-config ou P4_CONFIG),
+// par les variables d'environnement puis par les options de la ligne de commande.
+// Le journal (P4_LOG_LEVEL, P4_LOG_FORMAT) et le répertoire des sauvegardes (P4_DATA_DIR)
+// restent lus dans l'environnement seul : ils servent avant la lecture de la configuration.
+
+// duration : durée lue sous la forme "10s", "24h"... dans le fichier de configuration
+type duration struct{ time.Duration }
+
+func (d duration) MarshalJSON() ([]byte, error) { return json.Marshal(d.String()) }
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// boardLimits : taille des plateaux créés par /api/party/create
+type boardLimits struct {
+	MinRows     int `json:"minRows"`
+	MaxRows     int `json:"maxRows"`
+	MinCols     int `json:"minCols"`
+	MaxCols     int `json:"maxCols"`
+	DefaultRows int `json:"defaultRows"`
+	DefaultCols int `json:"defaultCols"`
+	WinLength   int `json:"winLength"`
+}
+
+// rateLimit : seau à jetons, Rate jetons par seconde et au plus Burst en réserve
+type rateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// rateLimits : création et arrivée limitées par adresse IP ; coups et boosters par session,
+// et par adresse IP avec une marge de IPFactor (plusieurs joueurs derrière une même adresse)
+type rateLimits struct {
+	Create   rateLimit `json:"create"`
+	Join     rateLimit `json:"join"`
+	Move     rateLimit `json:"move"`
+	Booster  rateLimit `json:"booster"`
+	IPFactor float64   `json:"ipFactor"`
+}
+
+type tlsConfig struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+// ttlConfig : durée d'inactivité au-delà de laquelle une partie est oubliée
+type ttlConfig struct {
+	Party duration `json:"party"` // Parties en direct
+	Async duration `json:"async"` // Parties par correspondance
+}
+
+// chatConfig : longueur des messages, débit par connexion et mots filtrés
+type chatConfig struct {
+	MaxLength   int      `json:"maxLength"`   // Longueur maximale d'un message (en caractères)
+	RateCount   int      `json:"rateCount"`   // Nombre de messages autorisés...
+	RateWindow  duration `json:"rateWindow"`  // ...sur cette fenêtre glissante
+	HistorySize int      `json:"historySize"` // Messages réaffichés au rechargement de la page
+	BannedWords []string `json:"bannedWords"`
+	BannedFile  string   `json:"bannedFile"` // Un mot filtré par ligne, "#" pour un commentaire
+}
+
+// notifierConfig : prévenir le joueur au trait d'une partie par correspondance
+type notifierConfig struct {
+	Kind       string `json:"kind"`      // log, webhook ou smtp
+	PublicURL  string `json:"publicURL"` // Adresse du serveur dans les liens envoyés
+	WebhookURL string `json:"webhookURL"`
+	SMTPAddr   string `json:"smtpAddr"`
+	SMTPFrom   string `json:"smtpFrom"`
+}
+
+// enginesConfig : moteurs externes qui peuvent s'asseoir dans une partie
+type enginesConfig struct {
+	Commands map[string]string `json:"commands"` // Nom -> commande et arguments
+	MoveTime duration          `json:"moveTime"` // Temps de réflexion à chaque coup
+}
+
+// Config : configuration du serveur et règles par défaut
+type Config struct {
+	Listen          string         `json:"listen"`
+	TLS             tlsConfig      `json:"tls"`
+	AllowedOrigins  []string       `json:"allowedOrigins"` // Vide = même origine uniquement
+	TrustProxy      bool           `json:"trustProxy"`     // Adresse du client lue dans X-Forwarded-For
+	Board           boardLimits    `json:"board"`
+	DefaultMode     string         `json:"defaultMode"`
+	Boosters        map[string]int `json:"boosters"` // Cases de chaque booster en mode turbo
+	CodeLength      int            `json:"codeLength"`
+	TTL             ttlConfig      `json:"ttl"`
+	RateLimits      rateLimits     `json:"rateLimits"`
+	ShutdownTimeout duration       `json:"shutdownTimeout"`
+	AssetsDir       string         `json:"assetsDir"`  // Ressources lues sur disque (développement), vide = embarquées
+	AdminToken      string         `json:"adminToken"` // Vide = administration désactivée
+	Chat            chatConfig     `json:"chat"`
+	Notifier        notifierConfig `json:"notifier"`
+	Engines         enginesConfig  `json:"engines"`
+}
+
+// knownModes : modes acceptés par /api/party/create, pour chaque variante de package game
+var knownModes = func() []string {
+	var modes []string
+	for _, solo := range []bool{true, false} {
+		for _, v := range game.Variants() {
+			modes = append(modes, modeName(solo, v))
+		}
+	}
+	return modes
+}()
+
+// cfg : configuration en vigueur, remplacée au démarrage par loadConfig
+var cfg = defaultConfig()
+
+func defaultConfig() Config {
+	return Config{
+		Listen: "0.0.0.0:8080",
+		Board: boardLimits{
+			MinRows: 4, MaxRows: 15, MinCols: 4, MaxCols: 15,
+			DefaultRows: 6, DefaultCols: 7, WinLength: 4,
+		},
+		DefaultMode: "solo-classique",
+		Boosters: map[string]int{
+			"double-shot": 2, "remove-piece": 2, "block-column": 2, "swap-colors": 2, "wildcard": 2,
+		},
+		CodeLength: 6,
+		TTL:        ttlConfig{Party: duration{24 * time.Hour}, Async: duration{30 * 24 * time.Hour}},
+		RateLimits: rateLimits{
+			Create:   rateLimit{Rate: 0.2, Burst: 5},
+			Join:     rateLimit{Rate: 1, Burst: 10},
+			Move:     rateLimit{Rate: 5, Burst: 10},
+			Booster:  rateLimit{Rate: 1, Burst: 5},
+			IPFactor: 4,
+		},
+		ShutdownTimeout: duration{10 * time.Second},
+		Chat: chatConfig{
+			MaxLength: 200, RateCount: 5, RateWindow: duration{10 * time.Second}, HistorySize: 50,
+		},
+		Notifier: notifierConfig{
+			Kind: "log", PublicURL: "http://localhost:8080",
+			SMTPAddr: "localhost:1025", SMTPFrom: "puissance4@localhost",
+		},
+		Engines: enginesConfig{MoveTime: duration{500 * time.Millisecond}},
+	}
+}
+
+// loadConfig construit la configuration à partir du fichier, de l'environnement et des options
+func loadConfig(args []string, getenv func(string) string) (Config, error) {
+	c := defaultConfig()
+	fs := flag.NewFlagSet("power4", flag.ContinueOnError)
+	file := fs.String("config", getenv("P4_CONFIG"), "fichier de configuration JSON")
+	listen := fs.String("listen", "", "adresse d'écoute (ex : :8080)")
+	cert := fs.String("tls-cert", "", "certificat TLS")
+	key := fs.String("tls-key", "", "clé privée TLS")
+	origins := fs.String("origins", "", "origines autorisées, séparées par des virgules")
+	mode := fs.String("default-mode", "", "mode par défaut des nouvelles parties")
+	assets := fs.String("assets", "", "répertoire des ressources web à lire sur disque (ex : web)")
+	if err := fs.Parse(args); err != nil {
+		return c, err
+	}
+
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return c, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return c, fmt.Errorf("%s : %w", *file, err)
+		}
+	}
+
+	if port := getenv("PORT"); port != "" {
+		c.Listen = "0.0.0.0:" + port
+	}
+	env := map[string]*string{
+		"P4_LISTEN":           &c.Listen,
+		"P4_TLS_CERT":         &c.TLS.Cert,
+		"P4_TLS_KEY":          &c.TLS.Key,
+		"P4_DEFAULT_MODE":     &c.DefaultMode,
+		"P4_ASSETS_DIR":       &c.AssetsDir,
+		"P4_ADMIN_TOKEN":      &c.AdminToken,
+		"P4_CHAT_BANNED_FILE": &c.Chat.BannedFile,
+		"P4_NOTIFIER":         &c.Notifier.Kind,
+		"P4_PUBLIC_URL":       &c.Notifier.PublicURL,
+		"P4_WEBHOOK_URL":      &c.Notifier.WebhookURL,
+		"P4_SMTP_ADDR":        &c.Notifier.SMTPAddr,
+		"P4_SMTP_FROM":        &c.Notifier.SMTPFrom,
+	}
+	for k, dst := range env {
+		if v := getenv(k); v != "" {
+			*dst = v
+		}
+	}
+	if v := getenv("P4_TRUST_PROXY"); v != "" {
+		c.TrustProxy = isTruthy(v)
+	}
+	if v := getenv("P4_ALLOWED_ORIGINS"); v != "" {
+		c.AllowedOrigins = splitList(v)
+	}
+	if v := getenv("P4_CHAT_BANNED_WORDS"); v != "" {
+		c.Chat.BannedWords = splitList(v)
+	}
+	if v := getenv("P4_ENGINES"); v != "" {
+		c.Engines.Commands = parseEngines(v)
+	}
+	for k, dst := range map[string]*duration{
+		"P4_SHUTDOWN_TIMEOUT": &c.ShutdownTimeout,
+		"P4_ENGINE_MOVETIME":  &c.Engines.MoveTime,
+	} {
+		if v := getenv(k); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return c, fmt.Errorf("%s : %w", k, err)
+			}
+			dst.Duration = d
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			c.Listen = *listen
+		case "tls-cert":
+			c.TLS.Cert = *cert
+		case "tls-key":
+			c.TLS.Key = *key
+		case "origins":
+			c.AllowedOrigins = splitList(*origins)
+		case "default-mode":
+			c.DefaultMode = *mode
+		case "assets":
+			c.AssetsDir = *assets
+		}
+	})
+	return c, c.validate()
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// validate vérifie la cohérence de la configuration et retourne toutes les erreurs trouvées
+func (c Config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	b := c.Board
+	check(c.Listen != "", "listen : adresse d'écoute manquante")
+	check((c.TLS.Cert == "") == (c.TLS.Key == ""), "tls : cert et key doivent être fournis ensemble")
+	for _, o := range c.AllowedOrigins {
+		check(o == "*" || absoluteURL(o), "allowedOrigins : origine invalide %q", o)
+	}
+	check(4 <= b.MinRows && b.MinRows <= b.DefaultRows && b.DefaultRows <= b.MaxRows && b.MaxRows <= 15,
+		"board : il faut 4 <= minRows <= defaultRows <= maxRows <= 15")
+	check(4 <= b.MinCols && b.MinCols <= b.DefaultCols && b.DefaultCols <= b.MaxCols && b.MaxCols <= 15,
+		"board : il faut 4 <= minCols <= defaultCols <= maxCols <= 15")
+	check(2 <= b.WinLength && b.WinLength <= max(b.MinRows, b.MinCols), "board : winLength doit être entre 2 et %d", max(b.MinRows, b.MinCols))
+	check(contains(knownModes, c.DefaultMode), "defaultMode : mode inconnu %q", c.DefaultMode)
+	total := 0
+	for name, n := range c.Boosters {
+		check(contains(game.BoosterTypes, name), "boosters : booster inconnu %q", name)
+		check(n >= 0, "boosters : nombre négatif pour %q", name)
+		total += n
+	}
+	check(total <= b.MinRows*b.MinCols, "boosters : %d cases booster pour un plateau de %d cases", total, b.MinRows*b.MinCols)
+	check(4 <= c.CodeLength && c.CodeLength <= 16, "codeLength doit être entre 4 et 16")
+	check(c.TTL.Party.Duration > 0 && c.TTL.Async.Duration > 0, "ttl : les durées doivent être positives")
+	for name, l := range map[string]rateLimit{
+		"create": c.RateLimits.Create, "join": c.RateLimits.Join, "move": c.RateLimits.Move, "booster": c.RateLimits.Booster,
+	} {
+		check(l.Rate > 0 && l.Burst >= 1, "rateLimits.%s : rate > 0 et burst >= 1 attendus", name)
+	}
+	check(c.RateLimits.IPFactor >= 1, "rateLimits.ipFactor doit être au moins 1")
+	check(c.ShutdownTimeout.Duration > 0, "shutdownTimeout doit être positif")
+	if c.AssetsDir != "" {
+		_, err := os.Stat(filepath.Join(c.AssetsDir, "templates", "index.html"))
+		check(err == nil, "assetsDir : %s/templates/index.html introuvable", c.AssetsDir)
+	}
+	ch := c.Chat
+	check(ch.MaxLength >= 1 && ch.RateCount >= 1 && ch.RateWindow.Duration > 0 && ch.HistorySize >= 0,
+		"chat : maxLength, rateCount et rateWindow positifs, historySize >= 0 attendus")
+	if ch.BannedFile != "" {
+		_, err := os.Stat(ch.BannedFile)
+		check(err == nil, "chat.bannedFile : %s introuvable", ch.BannedFile)
+	}
+	n := c.Notifier
+	check(contains([]string{"log", "webhook", "smtp"}, n.Kind), "notifier.kind : %q inconnu (log, webhook ou smtp)", n.Kind)
+	check(absoluteURL(n.PublicURL), "notifier.publicURL : adresse invalide %q", n.PublicURL)
+	check(n.Kind != "webhook" || absoluteURL(n.WebhookURL), "notifier.webhookURL : adresse invalide %q", n.WebhookURL)
+	check(n.Kind != "smtp" || (n.SMTPAddr != "" && n.SMTPFrom != ""), "notifier : smtpAddr et smtpFrom requis pour smtp")
+	for name, cmd := range c.Engines.Commands {
+		check(name != "" && name != localEngine, "engines.commands : nom réservé ou vide %q", name)
+		check(len(strings.Fields(cmd)) > 0, "engines.commands : commande vide pour %q", name)
+	}
+	check(c.Engines.MoveTime.Duration > 0, "engines.moveTime doit être positif")
+	return errors.Join(errs...)
+}
+
+// absoluteURL indique si s est une adresse complète (schéma et hôte)
+func absoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// clamp ramène une taille demandée dans les bornes configurées (0 = taille par défaut)
+func (b boardLimits) clamp(rows, cols int) (int, int) {
+	if rows == 0 {
+		rows = b.DefaultRows
+	}
+	if cols == 0 {
+		cols = b.DefaultCols
+	}
+	return min(max(rows, b.MinRows), b.MaxRows), min(max(cols, b.MinCols), b.MaxCols)
+}
+
+// configHandler expose la partie de la configuration utile aux clients
+func configHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"board":       cfg.Board,
+		"defaultMode": cfg.DefaultMode,
+		"modes":       knownModes,
+		"variants":    game.Variants(),
+		"boosters":    cfg.Boosters,
+		"codeLength":  cfg.CodeLength,
+		"rateLimits":  cfg.RateLimits,
+		"chat":        map[string]interface{}{"maxLength": cfg.Chat.MaxLength, "rateCount": cfg.Chat.RateCount, "rateWindow": cfg.Chat.RateWindow},
+		"engines":     engineNames(),
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "power4.json")
+	data := `{"listen": ":9000", "defaultMode": "multi-turbo", "board": {"minRows": 5, "maxRows": 10, "minCols": 5, "maxCols": 10, "defaultRows": 6, "defaultCols": 7, "winLength": 4}, "ttl": {"party": "1h", "async": "48h"}}`
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"P4_CONFIG": file, "PORT": "9100", "P4_SHUTDOWN_TIMEOUT": "3s"}
+	c, err := loadConfig([]string{"-default-mode", "multi-classique"}, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Listen != "0.0.0.0:9100" {
+		t.Errorf("listen = %q : l'environnement doit l'emporter sur le fichier", c.Listen)
+	}
+	if c.DefaultMode != "multi-classique" {
+		t.Errorf("defaultMode = %q : l'option doit l'emporter sur le fichier", c.DefaultMode)
+	}
+	if c.Board.MaxRows != 10 || c.TTL.Async.Duration != 48*time.Hour || c.ShutdownTimeout.Duration != 3*time.Second {
+		t.Errorf("configuration inattendue : %+v", c)
+	}
+	if c.Boosters["wildcard"] != 2 {
+		t.Errorf("les valeurs absentes du fichier doivent garder leur défaut")
+	}
+	if rows, cols := c.Board.clamp(20, 0); rows != 10 || cols != 7 {
+		t.Errorf("clamp(20, 0) = %d, %d", rows, cols)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := defaultConfig().validate(); err != nil {
+		t.Fatalf("configuration par défaut invalide : %v", err)
+	}
+	c := defaultConfig()
+	c.Board.MaxRows = 20
+	c.TLS.Cert = "cert.pem"
+	c.Boosters["teleport"] = 1
+	c.RateLimits.Move.Burst = 0
+	err := c.validate()
+	if err == nil {
+		t.Fatal("configuration invalide acceptée")
+	}
+	for _, want := range []string{"maxRows", "tls", "teleport", "rateLimits.move"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("erreur %q sans mention de %q", err, want)
+		}
+	}
+}
+
+func TestLoadConfigServices(t *testing.T) {
+	env := map[string]string{
+		"P4_ADMIN_TOKEN":       "sesame",
+		"P4_CHAT_BANNED_WORDS": "zut, flûte",
+		"P4_NOTIFIER":          "webhook",
+		"P4_WEBHOOK_URL":       "https://exemple.fr/tour",
+		"P4_ENGINES":           "ext=/usr/bin/moteur --fort",
+		"P4_ENGINE_MOVETIME":   "2s",
+	}
+	c, err := loadConfig(nil, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.AdminToken != "sesame" || len(c.Chat.BannedWords) != 2 || c.Notifier.Kind != "webhook" {
+		t.Errorf("configuration inattendue : %+v", c)
+	}
+	if cmd := engineCommands(c.Engines.Commands)["ext"]; len(cmd) != 2 || c.Engines.MoveTime.Duration != 2*time.Second {
+		t.Errorf("moteurs : %v, %v", cmd, c.Engines.MoveTime)
+	}
+
+	env["P4_WEBHOOK_URL"] = ""
+	env["P4_ENGINE_MOVETIME"] = "0s"
+	_, err = loadConfig(nil, func(k string) string { return env[k] })
+	for _, want := range []string{"notifier.webhookURL", "engines.moveTime"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("erreur %v sans mention de %q", err, want)
+		}
+	}
+}
+
+func TestExampleConfigIsValid(t *testing.T) {
+	env := map[string]string{"P4_CONFIG": "config.example.json"}
+	if _, err := loadConfig(nil, func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+}
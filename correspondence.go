@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"power4/game"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---------------- PARTIES PAR CORRESPONDANCE ----------------
+
+// Répertoire où sont sauvegardées les parties par correspondance
+var dataDir = envOr("P4_DATA_DIR", "data")
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "true", "yes", "on", "oui":
+		return true
+	}
+	return false
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// seatCookie : cookie qui porte dans le navigateur le jeton de siège de la partie code
+func seatCookie(code string) string {
+	return "seat_" + code
+}
+
+// requestToken retourne le jeton de siège de r : paramètre token (CLI, moteurs, formulaires)
+// ou cookie de la partie, posé par la page de jeu pour que le jeton ne passe pas dans l'adresse
+func requestToken(r *http.Request, code string) string {
+	if token := r.FormValue("token"); token != "" {
+		return token
+	}
+	if c, err := r.Cookie(seatCookie(code)); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// takeSeat assoit un joueur dans l'équipe team et retourne son jeton de siège.
+// p.Mu doit être verrouillé.
+func (p *Party) takeSeat(team, player, contact string) string {
+	if player == "" {
+		player = "Joueur " + team
+	}
+	p.Seats[team] = player
+	if contact != "" {
+		p.Contacts[team] = contact
+	}
+	token := newToken()
+	p.Tokens[token] = team
+	return token
+}
+
+// seatWithToken assoit player à l'équipe team avec un jeton déjà connu du joueur
+// (clé de tournoi). p.Mu doit être verrouillé.
+func (p *Party) seatWithToken(team, player, token string) {
+	p.Seats[team] = player
+	p.Tokens[token] = team
+}
+
+// seatOwned indique si un jeton de siège a été donné pour l'équipe team. p.Mu doit être verrouillé.
+func (p *Party) seatOwned(team string) bool {
+	for _, t := range p.Tokens {
+		if t == team {
+			return true
+		}
+	}
+	return false
+}
+
+// freeSeat retourne la première équipe sans joueur, ou "" si la partie est complète.
+func (p *Party) freeSeat() string {
+	for _, team := range p.seatList() {
+		if p.Seats[team] == "" {
+			return team
+		}
+	}
+	return ""
+}
+
+func saveParty(p *Party) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return err
+	}
+	// Écriture atomique pour ne pas corrompre la sauvegarde en cas d'arrêt brutal
+	tmp := filepath.Join(dataDir, p.Code+".json.tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dataDir, p.Code+".json"))
+}
+
+// loadParties recharge en mémoire les parties sauvegardées dans dataDir : parties par
+// correspondance et parties en cours lors du dernier arrêt du serveur.
+func loadParties() {
+	files, err := filepath.Glob(filepath.Join(dataDir, "*.json"))
+	if err != nil {
+		return
+	}
+	partiesMu.Lock()
+	defer partiesMu.Unlock()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			logger.Error("lecture de la sauvegarde impossible", "file", f, "err", err)
+			continue
+		}
+		p := newParty("", game.GameState{})
+		if err := json.Unmarshal(data, p); err != nil {
+			logger.Warn("sauvegarde invalide", "file", f, "err", err)
+			continue
+		}
+		if p.Seats == nil {
+			p.Seats = make(map[string]string)
+		}
+		if p.Contacts == nil {
+			p.Contacts = make(map[string]string)
+		}
+		if p.Tokens == nil {
+			p.Tokens = make(map[string]string)
+		}
+		if p.Match == nil {
+			p.Match = newMatch(1)
+		}
+		if p.State.Variant == "" {
+			// Sauvegarde antérieure aux variantes : tout était dans le mode
+			p.Solo, p.State.Variant = parseMode(p.State.Mode)
+		}
+		p.gameStart = time.Time{} // Début de la manche inconnu
+		parties[p.Code] = p
+		if !p.Async {
+			// Instantané pris à l'arrêt du serveur : la partie ne vit ensuite qu'en mémoire
+			if err := os.Remove(f); err != nil {
+				p.logger().Error("suppression de l'instantané impossible", "file", f, "err", err)
+			}
+		}
+		p.scheduleBot()
+	}
+	if len(files) > 0 {
+		logger.Info("parties rechargées", "count", len(files), "dir", dataDir)
+	}
+}
+
+// partyMoveHandler permet de jouer un coup en HTTP simple (POST code, token, col, row pour
+// les variantes sans gravité, et kind=pop pour retirer un pion en PopOut).
+func partyMoveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if refuseWhileDraining(w) {
+		return
+	}
+	code := strings.ToUpper(r.FormValue("code"))
+	col, err := strconv.Atoi(r.FormValue("col"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Colonne invalide")
+		return
+	}
+
+	partiesMu.Lock()
+	p, exists := parties[code]
+	partiesMu.Unlock()
+	if !exists {
+		writeJSONError(w, http.StatusNotFound, "Partie introuvable")
+		return
+	}
+
+	token := requestToken(r, code)
+	if err := limits.move.check(clientIP(r), token); err != nil {
+		writeRateLimited(w, r, err)
+		return
+	}
+
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	team, ok := p.Tokens[token]
+	if !ok {
+		writeJSONError(w, http.StatusForbidden, "Jeton de siège invalide")
+		return
+	}
+	row, _ := strconv.Atoi(r.FormValue("row"))
+	booster, err := p.play(team, game.Move{Kind: r.FormValue("kind"), Row: row, Col: col})
+	if err != nil {
+		status := http.StatusConflict
+		if err == errNotYourTurn {
+			status = http.StatusForbidden
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+	p.broadcastMove(nil, booster)
+
+	resp := map[string]interface{}{
+		"success": true,
+		"state":   p.stateFor(team),
+	}
+	if booster != "" {
+		resp["booster"] = booster
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"message": message,
+	})
+}
+
+// myGame : résumé d'une partie pour la liste "mes parties"
+type myGame struct {
+	Code      string    `json:"code"`
+	Mode      string    `json:"mode"`
+	Team      string    `json:"team"`
+	Opponents []string  `json:"opponents"` // Joueurs assis aux sièges des autres couleurs
+	YourTurn  bool      `json:"yourTurn"`
+	Finished  bool      `json:"finished"`
+	Winner    string    `json:"winner"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// listMyGames retourne les parties par correspondance d'un joueur, celles où c'est
+// son tour en premier.
+func listMyGames(player string) []myGame {
+	partiesMu.Lock()
+	list := make([]*Party, 0, len(parties))
+	for _, p := range parties {
+		list = append(list, p)
+	}
+	partiesMu.Unlock()
+
+	games := []myGame{}
+	for _, p := range list {
+		p.Mu.Lock()
+		if p.Async {
+			for team, name := range p.Seats {
+				if !strings.EqualFold(name, player) {
+					continue
+				}
+				var opponents []string
+				for _, seat := range p.seatList() {
+					if seatColor(seat) != seatColor(team) && p.Seats[seat] != "" {
+						opponents = append(opponents, p.Seats[seat])
+					}
+				}
+				games = append(games, myGame{
+					Code:      p.Code,
+					Mode:      p.State.Mode,
+					Team:      team,
+					Opponents: opponents,
+					YourTurn:  !p.State.Finished && p.turnSeat() == team,
+					Finished:  p.State.Finished,
+					Winner:    p.State.Winner,
+					UpdatedAt: p.UpdatedAt,
+				})
+			}
+		}
+		p.Mu.Unlock()
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if games[i].YourTurn != games[j].YourTurn {
+			return games[i].YourTurn
+		}
+		return games[i].UpdatedAt.After(games[j].UpdatedAt)
+	})
+	return games
+}
+
+func myGamesHandler(w http.ResponseWriter, r *http.Request) {
+	player := strings.TrimSpace(r.URL.Query().Get("player"))
+	if player == "" {
+		writeJSONError(w, http.StatusBadRequest, "Nom du joueur manquant")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(listMyGames(player))
+}
+
+func myGamesPageHandler(w http.ResponseWriter, r *http.Request) {
+	player := strings.TrimSpace(r.URL.Query().Get("player"))
+	data := struct {
+		Player string
+		Games  []myGame
+	}{Player: player}
+	if player != "" {
+		data.Games = listMyGames(player)
+	}
+	if err := pages.Execute(w, "mygames.html", data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// ---------------- NOTIFICATIONS ----------------
+
+// turnNotice décrit la notification envoyée quand c'est au tour d'un joueur
+type turnNotice struct {
+	Code    string `json:"code"`
+	Mode    string `json:"mode"`
+	Team    string `json:"team"`
+	Player  string `json:"player"`
+	Contact string `json:"contact,omitempty"`
+	URL     string `json:"url"`
+}
+
+// Notifier prévient un joueur que c'est à son tour de jouer
+type Notifier interface {
+	NotifyTurn(n turnNotice) error
+}
+
+type logNotifier struct{}
+
+func (logNotifier) NotifyTurn(n turnNotice) error {
+	logger.Info("à vous de jouer", "party", n.Code, "mode", n.Mode, "seat", n.Team, "player", n.Player, "contact", n.Contact)
+	return nil
+}
+
+// webhookNotifier poste la notification en JSON sur une URL
+type webhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (wn webhookNotifier) NotifyTurn(n turnNotice) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"event":  "your-turn",
+		"notice": n,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := wn.Client.Post(wn.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: statut %s", resp.Status)
+	}
+	return nil
+}
+
+// smtpNotifier envoie un e-mail au contact du joueur (serveur SMTP sans authentification)
+type smtpNotifier struct {
+	Addr string
+	From string
+}
+
+func (sn smtpNotifier) NotifyTurn(n turnNotice) error {
+	if !strings.Contains(n.Contact, "@") {
+		return logNotifier{}.NotifyTurn(n)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Puissance 4 - a toi de jouer (%s)\r\n\r\n"+
+		"Bonjour %s,\r\n\r\nC'est a ton tour dans la partie %s (%s).\r\n%s\r\n",
+		sn.From, n.Contact, n.Code, n.Player, n.Code, n.Mode, n.URL)
+	return smtp.SendMail(sn.Addr, nil, sn.From, []string{n.Contact}, []byte(msg))
+}
+
+// notifierURL ajoute le lien vers la partie avant de transmettre au notificateur choisi
+type notifierURL struct {
+	base  string
+	inner Notifier
+}
+
+func (nu notifierURL) NotifyTurn(n turnNotice) error {
+	n.URL = strings.TrimRight(nu.base, "/") + "/game?code=" + n.Code + "&team=" + n.Team
+	return nu.inner.NotifyTurn(n)
+}
+
+var notifier Notifier = newNotifier(cfg.Notifier)
+
+// newNotifier choisit le notificateur selon c.Kind (log, webhook, smtp).
+func newNotifier(c notifierConfig) Notifier {
+	var inner Notifier = logNotifier{}
+	switch c.Kind {
+	case "webhook":
+		inner = webhookNotifier{URL: c.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}
+	case "smtp":
+		inner = smtpNotifier{Addr: c.SMTPAddr, From: c.SMTPFrom}
+	}
+	return notifierURL{base: c.PublicURL, inner: inner}
+}
+
+// expireParties oublie régulièrement les parties inactives depuis plus que leur TTL
+// (cfg.TTL.Party en direct, cfg.TTL.Async par correspondance). Les parties avec des
+// clients connectés sont conservées.
+func expireParties() {
+	for range time.Tick(time.Minute) {
+		removeIdleParties(time.Now())
+	}
+}
+
+// removeIdleParties supprime les parties expirées à l'instant now et retourne leur nombre
+func removeIdleParties(now time.Time) int {
+	removed := 0
+	for _, p := range allParties() {
+		p.Mu.Lock()
+		ttl := cfg.TTL.Party.Duration
+		if p.Async {
+			ttl = cfg.TTL.Async.Duration
+		}
+		expired := len(p.Clients) == 0 && now.Sub(p.UpdatedAt) > ttl
+		if expired {
+			if p.Async {
+				if err := os.Remove(filepath.Join(dataDir, p.Code+".json")); err != nil && !os.IsNotExist(err) {
+					p.logger().Error("suppression de la sauvegarde impossible", "err", err)
+				}
+			}
+			p.stopBots()
+			p.logger().Info("partie expirée", "idle", now.Sub(p.UpdatedAt).Round(time.Second))
+		}
+		p.Mu.Unlock()
+		if expired {
+			partiesMu.Lock()
+			delete(parties, p.Code)
+			partiesMu.Unlock()
+			removed++
+		}
+	}
+	return removed
+}
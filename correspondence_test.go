@@ -0,0 +1,262 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"power4/game"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingNotifier garde les notifications de tour envoyées pour la partie code
+type recordingNotifier struct {
+	code    string
+	notices chan turnNotice
+}
+
+func (rn *recordingNotifier) NotifyTurn(n turnNotice) error {
+	if n.Code == rn.code {
+		rn.notices <- n
+	}
+	return nil
+}
+
+// newAsyncParty crée une partie par correspondance sauvegardée dans un répertoire temporaire,
+// R assis avec son jeton, Y libre
+func newAsyncParty(t *testing.T) (*Party, string) {
+	t.Helper()
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+	p := createParty(t, "variant=classique&async=1&team=R&player=Alice")
+	for token, team := range p.Tokens {
+		if team == "R" {
+			return p, token
+		}
+	}
+	t.Fatal("pas de jeton pour R")
+	return nil, ""
+}
+
+func postMove(code, token string, col int) *httptest.ResponseRecorder {
+	form := url.Values{"code": {code}, "token": {token}, "col": {strconv.Itoa(col)}}
+	req := httptest.NewRequest(http.MethodPost, "/api/party/move", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	partyMoveHandler(w, req)
+	return w
+}
+
+func TestSaveLoadParty(t *testing.T) {
+	p, token := newAsyncParty(t)
+	if w := postMove(p.Code, token, 3); w.Code != http.StatusOK {
+		t.Fatalf("coup : code %d (%s)", w.Code, w.Body)
+	}
+
+	partiesMu.Lock()
+	delete(parties, p.Code)
+	partiesMu.Unlock()
+	loadParties()
+	partiesMu.Lock()
+	q := parties[p.Code]
+	partiesMu.Unlock()
+	if q == nil {
+		t.Fatal("partie non rechargée")
+	}
+	if !q.Async || q.State.Board[5][3] != "R" || q.State.Next != "Y" || q.Seats["R"] != "Alice" || q.Tokens[token] != "R" {
+		t.Fatalf("partie rechargée : async=%v bas=%q next=%s sièges=%v", q.Async, q.State.Board[5], q.State.Next, q.Seats)
+	}
+}
+
+func TestPartyMoveRejectsWrongToken(t *testing.T) {
+	p, token := newAsyncParty(t)
+	for _, bad := range []string{"", "inconnu"} {
+		if w := postMove(p.Code, bad, 0); w.Code != http.StatusForbidden {
+			t.Errorf("jeton %q : code %d", bad, w.Code)
+		}
+	}
+	if p.State.Board[5][0] != "" {
+		t.Fatal("le coup refusé a été joué")
+	}
+	// Bon jeton mais pas son tour
+	postMove(p.Code, token, 0)
+	if w := postMove(p.Code, token, 1); w.Code != http.StatusForbidden {
+		t.Errorf("hors tour : code %d", w.Code)
+	}
+}
+
+func TestTurnNotifiedOncePerTurn(t *testing.T) {
+	rec := &recordingNotifier{notices: make(chan turnNotice, 8)}
+	prev := notifier
+	notifier = rec
+	t.Cleanup(func() { notifier = prev })
+
+	p, token := newAsyncParty(t)
+	rec.code = p.Code
+	p.Mu.Lock()
+	p.takeSeat("Y", "Bob", "bob@example.com")
+	p.Mu.Unlock()
+	postMove(p.Code, token, 3)
+
+	select {
+	case n := <-rec.notices:
+		if n.Team != "Y" || n.Player != "Bob" || n.Contact != "bob@example.com" {
+			t.Fatalf("notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pas de notification pour Y")
+	}
+	// Autres modifications pendant le même tour (chat, reconnexion...) : pas de nouvelle notification
+	p.Mu.Lock()
+	p.changed()
+	p.changed()
+	p.Mu.Unlock()
+	select {
+	case n := <-rec.notices:
+		t.Fatalf("notification en double : %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func postBooster(form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/booster-action", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	boosterActionHandler(w, req)
+	return w
+}
+
+func TestBoosterNeedsSeatTokenAndOwnership(t *testing.T) {
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+	p := createParty(t, "variant=turbo&async=1&team=R&player=Alice")
+	var token string
+	for tk := range p.Tokens {
+		token = tk
+	}
+	wildcard := func(player, token string) *httptest.ResponseRecorder {
+		return postBooster(url.Values{"action": {"wildcard"}, "code": {p.Code}, "player": {player}, "token": {token}, "row": {"0"}, "col": {"0"}})
+	}
+
+	if w := wildcard("R", ""); w.Code != http.StatusForbidden {
+		t.Fatalf("joker sans jeton : code %d", w.Code)
+	}
+	if w := wildcard("R", token); w.Code != http.StatusForbidden {
+		t.Fatalf("joker non ramassé : code %d", w.Code)
+	}
+
+	// R ramasse un joker en jouant sur sa case
+	p.Mu.Lock()
+	p.State.BoosterCells = [15][15]string{}
+	p.State.BoosterCells[5][3] = "wildcard"
+	_, err := p.playColumn("R", 3)
+	p.Mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := wildcard("Z", token); w.Code != http.StatusForbidden || p.State.Board[0][0] != "" {
+		t.Fatalf("joker pour une autre couleur : code %d, case %q", w.Code, p.State.Board[0][0])
+	}
+	if w := wildcard("", token); w.Code != http.StatusOK || p.State.Board[0][0] != "R" {
+		t.Fatalf("joker de R : code %d, case %q", w.Code, p.State.Board[0][0])
+	}
+	if w := wildcard("R", token); w.Code != http.StatusForbidden {
+		t.Fatalf("joker utilisé deux fois : code %d", w.Code)
+	}
+
+	q := createParty(t, "variant=classique")
+	if w := postBooster(url.Values{"action": {"double-shot"}, "code": {q.Code}, "player": {"R"}}); w.Code != http.StatusBadRequest {
+		t.Fatalf("booster hors turbo : code %d", w.Code)
+	}
+}
+
+func TestPopNotifiesNextPlayer(t *testing.T) {
+	rec := &recordingNotifier{notices: make(chan turnNotice, 8)}
+	prev := notifier
+	notifier = rec
+	t.Cleanup(func() { notifier = prev })
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+
+	p := createParty(t, "variant=popout&async=1&team=R&player=Alice")
+	rec.code = p.Code
+	p.Mu.Lock()
+	p.takeSeat("Y", "Bob", "bob@example.com")
+	for _, m := range []game.Move{{Col: 2}, {Col: 4}} {
+		if _, err := p.play(p.State.Next, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Mu.Unlock()
+	// Vider les notifications des deux premiers coups
+	for i := 0; i < 2; i++ {
+		select {
+		case <-rec.notices:
+		case <-time.After(time.Second):
+			t.Fatalf("notification %d manquante", i+1)
+		}
+	}
+
+	p.Mu.Lock()
+	_, err := p.play("R", game.Move{Kind: game.MovePop, Col: 2})
+	p.Mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case n := <-rec.notices:
+		if n.Team != "Y" || n.Player != "Bob" {
+			t.Fatalf("notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pas de notification après le retrait d'un pion")
+	}
+}
+
+func TestListMyGamesTeams(t *testing.T) {
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+	p := createParty(t, "variant=classique&async=1&teams=1&team=R1&player=Alice")
+	p.Mu.Lock()
+	p.takeSeat("Y1", "Bob", "")
+	p.takeSeat("R2", "Carol", "")
+	p.takeSeat("Y2", "Dave", "")
+	p.Mu.Unlock()
+
+	find := func(player string) myGame {
+		t.Helper()
+		for _, g := range listMyGames(player) {
+			if g.Code == p.Code {
+				return g
+			}
+		}
+		t.Fatalf("partie absente de la liste de %s", player)
+		return myGame{}
+	}
+	alice := find("Alice")
+	if alice.Team != "R1" || !alice.YourTurn || strings.Join(alice.Opponents, ",") != "Bob,Dave" {
+		t.Fatalf("Alice : %+v", alice)
+	}
+	if carol := find("Carol"); carol.YourTurn {
+		t.Fatalf("Carol, partenaire d'Alice, n'a pas le trait : %+v", carol)
+	}
+
+	p.Mu.Lock()
+	_, err := p.playColumn("R1", 3)
+	p.Mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bob := find("Bob"); !bob.YourTurn || strings.Join(bob.Opponents, ",") != "Alice,Carol" {
+		t.Fatalf("Bob : %+v", bob)
+	}
+	if alice := find("Alice"); alice.YourTurn {
+		t.Fatalf("Alice a encore le trait : %+v", alice)
+	}
+}
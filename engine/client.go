@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"power4/game"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTimeout   = errors.New("le moteur n'a pas répondu à temps")
+	ErrNoMove    = errors.New("réponse bestmove invalide")
+	ErrHandshake = errors.New("poignée de main p4e échouée")
+)
+
+// Engine est un moteur externe lancé comme sous-processus
+type Engine struct {
+	Name  string
+	Path  string
+	cmd   *exec.Cmd
+	in    io.WriteCloser
+	lines chan string
+}
+
+// Start lance l'exécutable (avec ses arguments) et effectue la poignée de main p4e
+func Start(path string, args ...string) (*Engine, error) {
+	cmd := exec.Command(path, args...)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	e := &Engine{Name: path, Path: path, cmd: cmd, in: in, lines: make(chan string, 64)}
+	go func() {
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			e.lines <- sc.Text()
+		}
+		close(e.lines)
+	}()
+
+	if err := e.send("p4e"); err != nil {
+		e.Close()
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		line, err := e.read(ctx)
+		if err != nil {
+			e.Close()
+			return nil, ErrHandshake
+		}
+		if name, ok := strings.CutPrefix(line, "id name "); ok {
+			e.Name = name
+		}
+		if line == "p4eok" {
+			return e, nil
+		}
+	}
+}
+
+func (e *Engine) send(format string, args ...interface{}) error {
+	_, err := fmt.Fprintf(e.in, format+"\n", args...)
+	return err
+}
+
+func (e *Engine) read(ctx context.Context) (string, error) {
+	select {
+	case line, ok := <-e.lines:
+		if !ok {
+			return "", io.EOF
+		}
+		return strings.TrimSpace(line), nil
+	case <-ctx.Done():
+		return "", ErrTimeout
+	}
+}
+
+// NewGame prévient le moteur qu'une nouvelle partie commence
+func (e *Engine) NewGame() error {
+	return e.send("newgame")
+}
+
+// BestMove envoie la position et attend "bestmove". Le moteur dispose du budget de son
+// horloge plus une marge (grace) avant d'être considéré comme hors délai.
+func (e *Engine) BestMove(position string, clock Clock, player string, grace time.Duration) (int, error) {
+	if err := e.send("%s", position); err != nil {
+		return -1, err
+	}
+	if err := e.send("%s", FormatGo(clock)); err != nil {
+		return -1, err
+	}
+	limit := clock.MoveTime
+	if limit == 0 {
+		limit = clock.WTime
+		if player == "Y" {
+			limit = clock.BTime
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), limit+grace)
+	defer cancel()
+	for {
+		line, err := e.read(ctx)
+		if err != nil {
+			return -1, err
+		}
+		if move, ok := strings.CutPrefix(line, "bestmove "); ok {
+			col, err := strconv.Atoi(strings.TrimSpace(move))
+			if err != nil {
+				return -1, ErrNoMove
+			}
+			return col, nil
+		}
+	}
+}
+
+// Close envoie "quit" et arrête le processus
+func (e *Engine) Close() error {
+	// Vider la sortie pour ne pas bloquer le lecteur pendant l'arrêt
+	go func() {
+		for range e.lines {
+		}
+	}()
+	_ = e.send("quit")
+	_ = e.in.Close()
+	done := make(chan error, 1)
+	go func() { done <- e.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Second):
+		_ = e.cmd.Process.Kill()
+		return <-done
+	}
+}
+
+// Serve fait tourner la boucle du protocole côté moteur : lit les commandes sur r et
+// répond sur w. choose reçoit la position et le budget de temps et retourne la colonne.
+func Serve(r io.Reader, w io.Writer, name, author string, choose func(st game.GameState, budget time.Duration) int) error {
+	sc := bufio.NewScanner(r)
+	st := game.GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R"}
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(w, format+"\n", args...)
+	}
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		cmd, _, _ := strings.Cut(line, " ")
+		switch cmd {
+		case "p4e":
+			reply("id name %s", name)
+			if author != "" {
+				reply("id author %s", author)
+			}
+			reply("p4eok")
+		case "isready":
+			reply("readyok")
+		case "newgame":
+			st = game.GameState{Rows: st.Rows, Cols: st.Cols, WinLength: st.WinLength, Next: "R"}
+		case "position":
+			if pos, err := ParsePosition(line); err == nil {
+				st = pos
+			}
+		case "go":
+			budget := ParseGo(line).Budget(st.Next)
+			reply("bestmove %d", choose(st, budget))
+		case "quit":
+			return nil
+		}
+	}
+	return sc.Err()
+}
@@ -0,0 +1,224 @@
+// Package engine définit le protocole texte (ligne par ligne sur stdin/stdout) utilisé
+// pour dialoguer avec des moteurs de Puissance 4 écrits dans n'importe quel langage,
+// dans l'esprit d'UCI pour les échecs.
+//
+// Dialogue (l'interface envoie à gauche, le moteur répond à droite) :
+//
+//	p4e                                        id name <nom>
+//	                                           id author <auteur>   (facultatif)
+//	                                           p4eok
+//	isready                                    readyok
+//	newgame
+//	position rows <R> cols <C> win <N> startpos [moves <c1> <c2> ...]
+//	position rows <R> cols <C> win <N> board <cases> next <R|Y> [moves ...]
+//	go movetime <ms>
+//	go wtime <ms> btime <ms> [winc <ms>] [binc <ms>]
+//	                                           info depth <d> score <s>  (facultatif)
+//	                                           bestmove <colonne>
+//	quit
+//
+// Les colonnes sont numérotées à partir de 0. Dans "board", les lignes sont données
+// de haut en bas, séparées par "/", chaque case valant "R", "Y" ou "." (vide).
+// Rouge ("R") commence depuis la position de départ. Les lignes inconnues sont ignorées.
+// Le protocole ne décrit que deux joueurs sur un plateau rectangulaire : les parties à plus
+// de deux joueurs ou sur un modèle de plateau sont réservées au moteur intégré.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"power4/game"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrBadPosition = errors.New("position invalide")
+
+// FormatPosition écrit la commande "position" décrivant st
+func FormatPosition(st game.GameState) string {
+	rows := make([]string, st.Rows)
+	for r := 0; r < st.Rows; r++ {
+		var b strings.Builder
+		for c := 0; c < st.Cols; c++ {
+			if cell := st.Board[r][c]; cell == "" {
+				b.WriteByte('.')
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		rows[r] = b.String()
+	}
+	return fmt.Sprintf("position rows %d cols %d win %d board %s next %s",
+		st.Rows, st.Cols, st.WinLength, strings.Join(rows, "/"), st.Next)
+}
+
+// FormatStartPosition écrit la commande "position" depuis un plateau vide suivi de coups
+func FormatStartPosition(rows, cols, win int, moves []int) string {
+	s := fmt.Sprintf("position rows %d cols %d win %d startpos", rows, cols, win)
+	if len(moves) > 0 {
+		parts := make([]string, len(moves))
+		for i, m := range moves {
+			parts[i] = strconv.Itoa(m)
+		}
+		s += " moves " + strings.Join(parts, " ")
+	}
+	return s
+}
+
+// ParsePosition lit une commande "position" et retourne l'état correspondant
+func ParsePosition(line string) (game.GameState, error) {
+	f := strings.Fields(line)
+	st := game.GameState{Next: "R", WinLength: 4}
+	if len(f) == 0 || f[0] != "position" {
+		return st, ErrBadPosition
+	}
+	var board string
+	var moves []int
+	for i := 1; i < len(f); i++ {
+		next := func() (string, error) {
+			if i+1 >= len(f) {
+				return "", ErrBadPosition
+			}
+			i++
+			return f[i], nil
+		}
+		var err error
+		var v string
+		switch f[i] {
+		case "rows", "cols", "win":
+			key := f[i]
+			if v, err = next(); err != nil {
+				return st, err
+			}
+			n, convErr := strconv.Atoi(v)
+			if convErr != nil || n < 1 || n > 15 {
+				return st, ErrBadPosition
+			}
+			switch key {
+			case "rows":
+				st.Rows = n
+			case "cols":
+				st.Cols = n
+			default:
+				st.WinLength = n
+			}
+		case "startpos":
+		case "board":
+			if board, err = next(); err != nil {
+				return st, err
+			}
+		case "next":
+			if v, err = next(); err != nil {
+				return st, err
+			}
+			if v != "R" && v != "Y" {
+				return st, ErrBadPosition
+			}
+			st.Next = v
+		case "moves":
+			for _, m := range f[i+1:] {
+				c, convErr := strconv.Atoi(m)
+				if convErr != nil {
+					return st, ErrBadPosition
+				}
+				moves = append(moves, c)
+			}
+			i = len(f)
+		}
+	}
+	if st.Rows == 0 || st.Cols == 0 {
+		return st, ErrBadPosition
+	}
+	if board != "" {
+		lines := strings.Split(board, "/")
+		if len(lines) != st.Rows {
+			return st, ErrBadPosition
+		}
+		for r, line := range lines {
+			if len(line) != st.Cols {
+				return st, ErrBadPosition
+			}
+			for c, ch := range line {
+				switch ch {
+				case 'R', 'Y':
+					st.Board[r][c] = string(ch)
+				case '.':
+				default:
+					return st, ErrBadPosition
+				}
+			}
+		}
+	}
+	for _, m := range moves {
+		if game.Drop(&st, m) < 0 {
+			return st, ErrBadPosition
+		}
+		st.Next = game.Opponent(st.Next)
+	}
+	return st, nil
+}
+
+// Clock : temps restant et incréments envoyés avec "go"
+type Clock struct {
+	MoveTime time.Duration // Temps fixe par coup (prioritaire s'il est non nul)
+	WTime    time.Duration // Temps restant de Rouge
+	BTime    time.Duration // Temps restant de Jaune
+	WInc     time.Duration
+	BInc     time.Duration
+}
+
+// FormatGo écrit la commande "go"
+func FormatGo(c Clock) string {
+	if c.MoveTime > 0 {
+		return fmt.Sprintf("go movetime %d", c.MoveTime.Milliseconds())
+	}
+	return fmt.Sprintf("go wtime %d btime %d winc %d binc %d",
+		c.WTime.Milliseconds(), c.BTime.Milliseconds(), c.WInc.Milliseconds(), c.BInc.Milliseconds())
+}
+
+// ParseGo lit une commande "go"
+func ParseGo(line string) Clock {
+	var c Clock
+	f := strings.Fields(line)
+	for i := 1; i+1 < len(f); i += 2 {
+		ms, err := strconv.Atoi(f[i+1])
+		if err != nil {
+			continue
+		}
+		d := time.Duration(ms) * time.Millisecond
+		switch f[i] {
+		case "movetime":
+			c.MoveTime = d
+		case "wtime":
+			c.WTime = d
+		case "btime":
+			c.BTime = d
+		case "winc":
+			c.WInc = d
+		case "binc":
+			c.BInc = d
+		}
+	}
+	return c
+}
+
+// Budget calcule le temps à consacrer au coup de player : le temps fixe s'il est donné,
+// sinon une fraction du temps restant plus l'incrément.
+func (c Clock) Budget(player string) time.Duration {
+	if c.MoveTime > 0 {
+		return c.MoveTime
+	}
+	left, inc := c.WTime, c.WInc
+	if player == "Y" {
+		left, inc = c.BTime, c.BInc
+	}
+	if left <= 0 {
+		return 100 * time.Millisecond
+	}
+	budget := left/20 + inc*3/4
+	if budget > left/2 {
+		budget = left / 2
+	}
+	return budget
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"bufio"
+	"io"
+	"power4/game"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPositionRoundTrip(t *testing.T) {
+	st := game.GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "Y"}
+	st.Board[5][3] = "R"
+	st.Board[5][4] = "Y"
+	st.Board[4][3] = "R"
+	got, err := ParsePosition(FormatPosition(st))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Board != st.Board || got.Next != "Y" || got.Rows != 6 || got.Cols != 7 || got.WinLength != 4 {
+		t.Fatalf("position relue différente : %+v", got)
+	}
+
+	got, err = ParsePosition(FormatStartPosition(6, 7, 4, []int{3, 4, 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Board != st.Board || got.Next != "Y" {
+		t.Fatalf("startpos + coups : %+v", got)
+	}
+
+	if _, err := ParsePosition("position rows 6 cols 7 win 4 board ...."); err == nil {
+		t.Fatal("plateau incohérent accepté")
+	}
+}
+
+func TestServeHandshakeAndBestMove(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		_ = Serve(inR, outW, "test", "", func(st game.GameState, budget time.Duration) int {
+			return game.LegalColumns(st)[0]
+		})
+		outW.Close()
+	}()
+	out := bufio.NewScanner(outR)
+	expect := func(prefix string) string {
+		t.Helper()
+		for out.Scan() {
+			if strings.HasPrefix(out.Text(), prefix) {
+				return out.Text()
+			}
+		}
+		t.Fatalf("%q non reçu", prefix)
+		return ""
+	}
+	io.WriteString(inW, "p4e\n")
+	expect("p4eok")
+	io.WriteString(inW, FormatStartPosition(6, 7, 4, nil)+"\n"+FormatGo(Clock{MoveTime: 50 * time.Millisecond})+"\n")
+	if got := expect("bestmove"); got != "bestmove 0" {
+		t.Fatalf("réponse %q", got)
+	}
+	io.WriteString(inW, "quit\n")
+}
@@ -0,0 +1,308 @@
+package game
+
+import (
+	"math"
+	"time"
+)
+
+// Scores utilisés par la recherche
+const (
+	winScore = 1_000_000
+	infinity = math.MaxInt32
+)
+
+// BestMove choisit une colonne pour st.Next par une recherche alpha-bêta à profondeur
+// croissante, arrêtée quand le budget de temps est écoulé. Retourne -1 si aucun coup
+// n'est possible.
+func BestMove(st GameState, budget time.Duration) int {
+	move, _, _ := Search(st, budget, 0)
+	return move
+}
+
+// Search est BestMove avec une profondeur maximale (0 = illimitée) ; retourne aussi
+// le score et la profondeur atteinte.
+func Search(st GameState, budget time.Duration, maxDepth int) (move, score, depth int) {
+	legal := orderedColumns(st)
+	if len(legal) == 0 {
+		return -1, 0, 0
+	}
+	if st.WinLength <= 0 {
+		st.WinLength = 4
+	}
+	s := &searcher{st: st, me: st.Next, deadline: time.Now().Add(budget)}
+	if _, ok := VariantOf(st).(goalRules); ok {
+		s.goal = st.Variant
+	}
+	s.wrapH, s.wrapV = Wraps(st)
+	move = legal[0]
+	limit := st.Rows * st.Cols
+	if maxDepth > 0 && maxDepth < limit {
+		limit = maxDepth
+	}
+	for d := 1; d <= limit; d++ {
+		m, sc, ok := s.root(d)
+		if !ok {
+			break
+		}
+		move, score, depth = m, sc, d
+		if sc >= winScore-limit || sc <= -winScore+limit {
+			break // Gain ou perte forcé trouvé
+		}
+	}
+	return move, score, depth
+}
+
+// orderedColumns retourne les colonnes jouables, du centre vers les bords
+func orderedColumns(st GameState) []int {
+	legal := LegalColumns(st)
+	center := float64(st.Cols-1) / 2
+	for i := 1; i < len(legal); i++ {
+		for j := i; j > 0 && math.Abs(float64(legal[j])-center) < math.Abs(float64(legal[j-1])-center); j-- {
+			legal[j], legal[j-1] = legal[j-1], legal[j]
+		}
+	}
+	return legal
+}
+
+// searcher : à plus de deux joueurs, la recherche est « paranoïaque » : les adversaires de me
+// jouent tous ensemble contre lui, ce qui ramène la partie à deux camps.
+type searcher struct {
+	st       GameState
+	me       string // Joueur pour qui la recherche choisit un coup
+	goal     string // Condition de fin de la variante (GoalMisere...), "" pour le premier alignement
+	wrapH    bool   // Bords gauche et droit rejoints (variante tore)
+	wrapV    bool   // Bords haut et bas rejoints
+	deadline time.Time
+	nodes    int
+	aborted  bool
+}
+
+func (s *searcher) timeUp() bool {
+	s.nodes++
+	if s.nodes%1024 == 0 && time.Now().After(s.deadline) {
+		s.aborted = true
+	}
+	return s.aborted
+}
+
+func (s *searcher) root(depth int) (int, int, bool) {
+	best, bestScore := -1, -infinity
+	alpha := -infinity
+	for _, c := range orderedColumns(s.st) {
+		score := s.play(c, depth, alpha, infinity, 1)
+		if s.aborted {
+			return 0, 0, false
+		}
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+	return best, bestScore, true
+}
+
+// play joue c, cherche la réponse du joueur suivant puis annule le coup (score et fenêtre
+// alpha-bêta du point de vue du joueur qui joue c)
+func (s *searcher) play(c, depth, alpha, beta, ply int) int {
+	st := &s.st
+	player := st.Next
+	r := Drop(st, c)
+	defer func() {
+		st.Board[r][c] = ""
+		st.Next = player
+	}()
+	switch s.goal {
+	case "":
+		if s.lineThrough(r, c) {
+			return winScore - ply
+		}
+	case GoalMisere:
+		if s.lineThrough(r, c) {
+			return -winScore + ply
+		}
+	case GoalExact:
+		if exactLineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, c) {
+			return winScore - ply
+		}
+	}
+	st.Next = NextPlayer(*st, player)
+	if s.side(st.Next) == s.side(player) {
+		return s.negamax(depth-1, alpha, beta, ply+1) // Deux adversaires de me à la suite
+	}
+	return -s.negamax(depth-1, -beta, -alpha, ply+1)
+}
+
+// side indique si player est dans le camp de me
+func (s *searcher) side(player string) bool {
+	return player == s.me
+}
+
+// pov retourne +1 si le joueur au trait est me, -1 s'il est dans le camp adverse
+func (s *searcher) pov() int {
+	if s.side(s.st.Next) {
+		return 1
+	}
+	return -1
+}
+
+// lineThrough indique si le pion en (r, c) fait partie d'un alignement, par les bords rejoints compris
+func (s *searcher) lineThrough(r, c int) bool {
+	st := &s.st
+	if s.wrapH || s.wrapV {
+		return wrapLine(&st.Board, st.Rows, st.Cols, st.WinLength, r, c, s.wrapH, s.wrapV) != nil
+	}
+	return LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, c)
+}
+
+func (s *searcher) negamax(depth, alpha, beta, ply int) int {
+	if s.timeUp() {
+		return 0
+	}
+	legal := orderedColumns(s.st)
+	if len(legal) == 0 {
+		return s.final(ply)
+	}
+	if depth == 0 {
+		return s.evaluate()
+	}
+	best := -infinity
+	for _, c := range legal {
+		score := s.play(c, depth, alpha, beta, ply)
+		if score > best {
+			best = score
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+// final note un plateau plein pour le camp du joueur au trait : nulle, sauf en variante lignes
+// où le joueur qui a le plus d'alignements gagne
+func (s *searcher) final(ply int) int {
+	if s.goal != GoalLines {
+		return 0
+	}
+	switch diff := s.pov() * s.linesLead(); {
+	case diff > 0:
+		return winScore - ply
+	case diff < 0:
+		return -winScore + ply
+	}
+	return 0
+}
+
+// linesLead retourne l'avance de me en alignements sur le meilleur de ses adversaires
+func (s *searcher) linesLead() int {
+	lines := CountLines(s.st)
+	best := 0
+	for p, n := range lines {
+		if p != s.me && n > best {
+			best = n
+		}
+	}
+	return lines[s.me] - best
+}
+
+// evaluate note la position pour le camp du joueur au trait selon la condition de fin
+func (s *searcher) evaluate() int {
+	score := Evaluate(s.st, s.me)
+	switch s.goal {
+	case GoalMisere:
+		score = -score // Les alignements en préparation sont un danger
+	case GoalLines:
+		score += 100 * s.linesLead()
+	}
+	return s.pov() * score
+}
+
+// LineThrough indique si le pion en (r, c) fait partie d'un alignement de winLength pions
+func LineThrough(board [15][15]string, rows, cols, winLength, r, c int) bool {
+	for _, n := range runsThrough(board, rows, cols, r, c) {
+		if n >= winLength {
+			return true
+		}
+	}
+	return false
+}
+
+// exactLineThrough indique si le pion en (r, c) fait partie d'un alignement d'exactement winLength pions
+func exactLineThrough(board [15][15]string, rows, cols, winLength, r, c int) bool {
+	for _, n := range runsThrough(board, rows, cols, r, c) {
+		if n == winLength {
+			return true
+		}
+	}
+	return false
+}
+
+// runsThrough retourne la longueur de l'alignement passant par (r, c) dans chaque direction
+// (zéro si la case ne contient pas le pion d'un joueur)
+func runsThrough(board [15][15]string, rows, cols, r, c int) [4]int {
+	var runs [4]int
+	p := board[r][c]
+	if !IsDisc(p) {
+		return runs
+	}
+	for i, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+		n := 1
+		for _, sign := range []int{1, -1} {
+			rr, cc := r+sign*d[0], c+sign*d[1]
+			for rr >= 0 && rr < rows && cc >= 0 && cc < cols && board[rr][cc] == p {
+				n++
+				rr += sign * d[0]
+				cc += sign * d[1]
+			}
+		}
+		runs[i] = n
+	}
+	return runs
+}
+
+// Evaluate note la position pour player : chaque fenêtre de winLength cases ne contenant
+// les pions que d'un seul joueur rapporte d'autant plus qu'elle est remplie. Sur un tore, les
+// fenêtres continuent par les bords qui se rejoignent.
+func Evaluate(st GameState, player string) int {
+	score := 0
+	n := st.WinLength
+	h, v := Wraps(st)
+	h, v = h && n <= st.Cols, v && n <= st.Rows // Une fenêtre ne repasse pas par la même case
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			for _, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+				if _, _, ok := wrapStep(st.Rows, st.Cols, r+(n-1)*d[0], c+(n-1)*d[1], h, v); !ok {
+					continue
+				}
+				mine, theirs, dead := 0, 0, false
+				for i := 0; i < n; i++ {
+					rr, cc, _ := wrapStep(st.Rows, st.Cols, r+i*d[0], c+i*d[1], h, v)
+					switch cell := st.Board[rr][cc]; {
+					case cell == "":
+					case cell == player:
+						mine++
+					case IsDisc(cell):
+						theirs++
+					default:
+						dead = true // Pion neutralisé, case bloquée ou trou : fenêtre impossible
+					}
+				}
+				if dead {
+					continue
+				}
+				switch {
+				case theirs == 0 && mine > 0:
+					score += mine * mine
+				case mine == 0 && theirs > 0:
+					score -= theirs * theirs
+				}
+			}
+		}
+	}
+	return score
+}
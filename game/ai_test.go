@@ -0,0 +1,53 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBestMoveWinsImmediately(t *testing.T) {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R"}
+	for r := 3; r < 6; r++ {
+		st.Board[r][2] = "R"
+		st.Board[r][5] = "Y"
+	}
+	if got := BestMove(st, 200*time.Millisecond); got != 2 {
+		t.Fatalf("BestMove = %d, attendu 2 (victoire verticale)", got)
+	}
+}
+
+func TestBestMoveBlocksThreat(t *testing.T) {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R"}
+	st.Board[5][1], st.Board[5][2], st.Board[5][3] = "Y", "Y", "Y"
+	st.Board[4][2], st.Board[4][3] = "R", "R"
+	st.Board[5][4] = "R" // Seule la colonne 0 complète l'alignement adverse
+	if got := BestMove(st, 200*time.Millisecond); got != 0 {
+		t.Fatalf("BestMove = %d, attendu 0 pour bloquer", got)
+	}
+}
+
+func TestBestMoveFullBoard(t *testing.T) {
+	st := GameState{Rows: 4, Cols: 4, WinLength: 4, Next: "R"}
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			st.Board[r][c] = "X"
+		}
+	}
+	if got := BestMove(st, 10*time.Millisecond); got != -1 {
+		t.Fatalf("BestMove = %d sur plateau plein", got)
+	}
+}
+
+func TestBestMoveBlocksThirdPlayer(t *testing.T) {
+	// R peut ouvrir un trois (colonne 1 ou 4) que Y seul ne bloquerait pas, mais G joue
+	// après Y : R doit d'abord fermer la colonne 6
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Players: []string{"R", "Y", "G"}}
+	for r := 3; r < 6; r++ {
+		st.Board[r][6] = "G"
+	}
+	st.Board[5][2], st.Board[5][3] = "R", "R"
+	st.Board[4][2], st.Board[4][3] = "Y", "Y"
+	if got := BestMove(st, 200*time.Millisecond); got != 6 {
+		t.Fatalf("BestMove = %d, attendu 6 pour bloquer G", got)
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// Règles d'un conflit au blitz, quand les deux joueurs choisissent la même colonne
+const (
+	ConflictCoin  = "coin"  // Tirage au sort : seul le pion du gagnant est posé
+	ConflictStack = "stack" // Les deux pions s'empilent, celui du joueur prioritaire en premier
+)
+
+// defaultRoundTime : durée d'un tour de blitz, sauf option round (en secondes)
+const defaultRoundTime = 10 * time.Second
+
+// Simultaneous : variante où les joueurs choisissent leur colonne en même temps, tour par tour.
+// Les coups ne passent pas par Play mais par PlayRound.
+type Simultaneous interface {
+	// RoundTime : temps laissé aux joueurs pour choisir, à partir du premier choix du tour
+	RoundTime(st GameState) time.Duration
+}
+
+// blitz : les deux joueurs choisissent une colonne sans voir le choix de l'autre, puis les
+// deux pions tombent ensemble. Même colonne : tirage au sort (option conflict=coin, par
+// défaut) ou les deux pions s'empilent (conflict=stack). Le joueur au trait (st.Next) est
+// prioritaire, la priorité change à chaque tour. Deux alignements dans le même tour : nulle.
+type blitz struct{ dropRules }
+
+// MaxPlayers : un tour se joue à deux
+func (b blitz) MaxPlayers() int { return 2 }
+
+func (b blitz) RoundTime(st GameState) time.Duration {
+	if s, err := strconv.Atoi(st.Options["round"]); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	return defaultRoundTime
+}
+
+// Apply : les coups d'un tour sont résolus ensemble par PlayRound
+func (b blitz) Apply(st *GameState, m Move) (Outcome, error) {
+	return Outcome{}, ErrIllegalMove
+}
+
+// Placed : pion posé pendant un tour de blitz
+type Placed struct {
+	Player string `json:"player"`
+	Row    int    `json:"row"`
+	Col    int    `json:"col"`
+}
+
+// RoundResult : résolution d'un tour de blitz
+type RoundResult struct {
+	Placed []Placed `json:"placed"`         // Pions posés, dans l'ordre
+	Lost   []string `json:"lost,omitempty"` // Joueurs dont le pion n'a pas été posé (tirage perdu, colonne remplie)
+	Coin   string   `json:"coin,omitempty"` // Gagnant du tirage au sort
+}
+
+// PlayRound résout un tour d'une variante Simultaneous : picks donne la colonne choisie par
+// chaque joueur (un joueur absent n'a pas joué à temps). Les pions tombent, puis la manche se
+// termine sur un alignement ou un plateau plein ; sinon la priorité passe à l'autre joueur.
+func PlayRound(st *GameState, picks map[string]int, rng *rand.Rand) (RoundResult, error) {
+	var res RoundResult
+	if _, ok := VariantOf(*st).(Simultaneous); !ok || st.Finished {
+		return res, ErrIllegalMove
+	}
+	first := st.Next
+	order := []string{first, Opponent(first)}
+	for p, col := range picks {
+		if !contains(order, p) || col < 0 || col >= st.Cols {
+			return res, ErrIllegalMove
+		}
+	}
+	c1, ok1 := picks[order[0]]
+	c2, ok2 := picks[order[1]]
+	if ok1 && ok2 && c1 == c2 && st.Options["conflict"] != ConflictStack {
+		res.Coin = order[rng.Intn(2)]
+		res.Lost = append(res.Lost, Opponent(res.Coin))
+		order = []string{res.Coin}
+	}
+
+	var winners []string
+	for _, p := range order {
+		col, ok := picks[p]
+		if !ok {
+			continue
+		}
+		st.Next = p
+		row := Drop(st, col)
+		if row < 0 {
+			res.Lost = append(res.Lost, p) // Colonne remplie par le pion prioritaire
+			continue
+		}
+		res.Placed = append(res.Placed, Placed{Player: p, Row: row, Col: col})
+	}
+	for _, pl := range res.Placed {
+		if LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, pl.Row, pl.Col) && !contains(winners, pl.Player) {
+			winners = append(winners, pl.Player)
+		}
+	}
+
+	st.Next = Opponent(first)
+	switch {
+	case len(winners) == 1:
+		st.Finished, st.Winner = true, winners[0]
+	case len(winners) > 1 || BoardFull(st.Board, st.Rows, st.Cols):
+		st.Finished = true // Double alignement : nulle
+	}
+	return res, nil
+}
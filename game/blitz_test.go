@@ -0,0 +1,74 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newBlitz(conflict string) GameState {
+	return GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: "blitz", Options: Options{"conflict": conflict}}
+}
+
+func TestPlayRoundDifferentColumns(t *testing.T) {
+	st := newBlitz("")
+	res, err := PlayRound(&st, map[string]int{"R": 0, "Y": 3}, rand.New(rand.NewSource(1)))
+	if err != nil || len(res.Placed) != 2 || st.Board[5][0] != "R" || st.Board[5][3] != "Y" {
+		t.Fatalf("err=%v placed=%v rangée %q", err, res.Placed, st.Board[5])
+	}
+	if st.Next != "Y" {
+		t.Fatalf("la priorité passe à Y : %s", st.Next)
+	}
+	if _, err := Play(&st, Move{Col: 1}); err != ErrIllegalMove {
+		t.Fatalf("un coup seul est refusé : %v", err)
+	}
+}
+
+func TestPlayRoundConflict(t *testing.T) {
+	st := newBlitz(ConflictCoin)
+	res, _ := PlayRound(&st, map[string]int{"R": 2, "Y": 2}, rand.New(rand.NewSource(1)))
+	if res.Coin == "" || len(res.Placed) != 1 || res.Placed[0].Player != res.Coin || st.Board[4][2] != "" {
+		t.Fatalf("tirage au sort : %+v", res)
+	}
+
+	st = newBlitz(ConflictStack)
+	st.Next = "Y"
+	res, _ = PlayRound(&st, map[string]int{"R": 2, "Y": 2}, rand.New(rand.NewSource(1)))
+	if len(res.Placed) != 2 || st.Board[5][2] != "Y" || st.Board[4][2] != "R" {
+		t.Fatalf("pions empilés, prioritaire en bas : %+v", res)
+	}
+}
+
+func TestPlayRoundDoubleWinIsDraw(t *testing.T) {
+	st := newBlitz("")
+	for c := 0; c < 3; c++ {
+		st.Board[5][c] = "R"
+		st.Board[4][c] = "Y"
+	}
+	res, _ := PlayRound(&st, map[string]int{"R": 3, "Y": 3}, rand.New(rand.NewSource(1)))
+	// Tirage au sort : un seul pion tombe en (5,3), seul R peut y aligner
+	if won := res.Coin == "R"; res.Coin == "" || st.Finished != won || won && st.Winner != "R" {
+		t.Fatalf("même colonne, tirage au sort : %+v finished=%v winner=%q", res, st.Finished, st.Winner)
+	}
+
+	st = newBlitz(ConflictStack)
+	for c := 0; c < 3; c++ {
+		st.Board[5][c] = "R"
+		st.Board[4][c] = "Y"
+	}
+	// R en (5,3) puis Y en (4,3) : deux alignements dans le même tour
+	PlayRound(&st, map[string]int{"R": 3, "Y": 3}, rand.New(rand.NewSource(1)))
+	if !st.Finished || st.Winner != "" {
+		t.Fatalf("double alignement : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestPlayRoundMissingPick(t *testing.T) {
+	st := newBlitz("")
+	res, err := PlayRound(&st, map[string]int{"Y": 4}, rand.New(rand.NewSource(1)))
+	if err != nil || len(res.Placed) != 1 || st.Board[5][4] != "Y" {
+		t.Fatalf("R n'a pas choisi : err=%v %+v", err, res)
+	}
+	if _, err := PlayRound(&st, map[string]int{"G": 0}, rand.New(rand.NewSource(1))); err != ErrIllegalMove {
+		t.Fatalf("joueur absent de la partie : %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package game
+
+// BoardFull indique si toutes les cases du plateau (rows x cols) sont occupées.
+func BoardFull(board [15][15]string, rows, cols int) bool {
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if board[r][c] == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Drop fait tomber un pion de st.Next dans la colonne col et retourne la ligne atteinte,
+// ou -1 si la colonne est pleine ou hors du plateau. Ni le vainqueur ni le tour ne changent.
+func Drop(st *GameState, col int) int {
+	if col < 0 || col >= st.Cols {
+		return -1
+	}
+	r := dropRow(&st.Board, st.Rows, col)
+	if r >= 0 {
+		st.Board[r][col] = st.Next
+	}
+	return r
+}
+
+// dropRow retourne la ligne où s'arrête un pion lâché dans la colonne col, -1 si elle est
+// pleine. Le pion passe au travers des trous et s'arrête sur un pion, une case bloquée ou le fond.
+func dropRow(board *[15][15]string, rows, col int) int {
+	row := -1
+	for r := 0; r < rows; r++ {
+		switch board[r][col] {
+		case "":
+			row = r
+		case Hole:
+		default:
+			return row
+		}
+	}
+	return row
+}
+
+// LegalColumns retourne les colonnes non pleines
+func LegalColumns(st GameState) []int {
+	var cols []int
+	for c := 0; c < st.Cols; c++ {
+		// La case du haut suffit, sauf sous un trou d'un modèle de plateau
+		if st.Board[0][c] == "" || (st.Board[0][c] == Hole && dropRow(&st.Board, st.Rows, c) >= 0) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+// Opponent retourne le jeton adverse
+func Opponent(token string) string {
+	if token == "R" {
+		return "Y"
+	}
+	return "R"
+}
@@ -0,0 +1,136 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Hidden : pion adverse que le joueur ne voit pas (variante brouillard). La case est occupée,
+// sa couleur est inconnue.
+const Hidden = "?"
+
+// MoveScout : brouillard, envoyer un éclaireur qui dévoile la case (Row, Col) et ses voisines
+const MoveScout = "scout"
+
+// defaultScouts : éclaireurs de chaque joueur par manche, sauf option scouts
+const defaultScouts = 2
+
+// Concealed : variante à information cachée ; chaque joueur ne reçoit que sa vue de la partie
+type Concealed interface {
+	// View retourne st telle que player la voit ("" : spectateur)
+	View(st GameState, player string) GameState
+}
+
+// View retourne st vue par player : la partie entière, sauf pendant une manche d'une variante
+// à information cachée
+func View(st GameState, player string) GameState {
+	if c, ok := VariantOf(st).(Concealed); ok && !st.Finished {
+		return c.View(st, player)
+	}
+	return st
+}
+
+// fog : Puissance 4 dans le brouillard. Chacun ne voit que ses pions, les pions adverses qui
+// touchent un des siens (les huit cases voisines) et les cases dévoilées par ses éclaireurs ;
+// tout le plateau est dévoilé en fin de manche. Envoyer un éclaireur ne coûte pas le tour.
+// Option scouts=N : éclaireurs par joueur et par manche (2 par défaut).
+type fog struct{ dropRules }
+
+func (f fog) Init(st *GameState, rng *rand.Rand) {
+	n, err := strconv.Atoi(st.Options["scouts"])
+	if err != nil || n < 0 {
+		n = defaultScouts
+	}
+	st.Scouts = make(map[string]int)
+	for _, p := range PlayersOf(*st) {
+		st.Scouts[p] = n
+	}
+	st.Revealed = nil
+}
+
+func (f fog) Apply(st *GameState, m Move) (Outcome, error) {
+	if m.Kind != MoveScout {
+		return f.dropRules.Apply(st, m)
+	}
+	player := st.Next
+	if st.Scouts[player] <= 0 || m.Row < 0 || m.Row >= st.Rows || m.Col < 0 || m.Col >= st.Cols {
+		return Outcome{}, ErrIllegalMove
+	}
+	// Copie avant écriture : des copies de l'état (vues, recherche) peuvent partager les tables
+	scouts := make(map[string]int, len(st.Scouts))
+	for p, n := range st.Scouts {
+		scouts[p] = n
+	}
+	scouts[player]--
+	revealed := make(map[string][][2]int, len(st.Revealed)+1)
+	for p, cells := range st.Revealed {
+		revealed[p] = cells
+	}
+	cells := append([][2]int(nil), revealed[player]...)
+	for r := m.Row - 1; r <= m.Row+1; r++ {
+		for c := m.Col - 1; c <= m.Col+1; c++ {
+			if r >= 0 && r < st.Rows && c >= 0 && c < st.Cols {
+				cells = append(cells, [2]int{r, c})
+			}
+		}
+	}
+	revealed[player] = cells
+	st.Scouts, st.Revealed = scouts, revealed
+	return Outcome{Row: m.Row, Col: m.Col, Next: player}, nil
+}
+
+func (f fog) View(st GameState, player string) GameState {
+	var visible [15][15]bool
+	for _, cell := range st.Revealed[player] {
+		visible[cell[0]][cell[1]] = true
+	}
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if player == "" || st.Board[r][c] != player {
+				continue
+			}
+			for rr := max(r-1, 0); rr <= min(r+1, st.Rows-1); rr++ {
+				for cc := max(c-1, 0); cc <= min(c+1, st.Cols-1); cc++ {
+					visible[rr][cc] = true
+				}
+			}
+		}
+	}
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if IsDisc(st.Board[r][c]) && st.Board[r][c] != player && !visible[r][c] {
+				st.Board[r][c] = Hidden
+			}
+		}
+	}
+	// Les éclaireurs des autres joueurs restent secrets
+	cells := st.Revealed[player]
+	st.Revealed = nil
+	if cells != nil {
+		st.Revealed = map[string][][2]int{player: cells}
+	}
+	return st
+}
+
+// FormatMove : "3" pour faire tomber un pion, "e2,3" pour un éclaireur en (2, 3)
+func (f fog) FormatMove(m Move) string {
+	if m.Kind == MoveScout {
+		return fmt.Sprintf("e%d,%d", m.Row, m.Col)
+	}
+	return strconv.Itoa(m.Col)
+}
+
+func (f fog) ParseMove(s string) (Move, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "e"); ok {
+		var m Move
+		if _, err := fmt.Sscanf(rest, "%d,%d", &m.Row, &m.Col); err != nil {
+			return Move{}, fmt.Errorf("éclaireur invalide : %q", s)
+		}
+		m.Kind = MoveScout
+		return m, nil
+	}
+	return f.dropRules.ParseMove(s)
+}
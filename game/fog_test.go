@@ -0,0 +1,49 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newFog() GameState {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: "brouillard", Options: Options{"scouts": "1"}}
+	VariantOf(st).Init(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func TestFogView(t *testing.T) {
+	st := newFog()
+	playAll(t, &st, Move{Col: 0}, Move{Col: 1}, Move{Col: 3}, Move{Col: 6})
+	r := View(st, "R")
+	if r.Board[5][1] != "Y" || r.Board[5][6] != Hidden || r.Board[5][0] != "R" {
+		t.Fatalf("vue de R : %q", r.Board[5])
+	}
+	if st.Board[5][6] != "Y" {
+		t.Fatal("la vue ne doit pas modifier l'état")
+	}
+	if s := View(st, ""); s.Board[5][0] != Hidden || s.Board[5][1] != Hidden {
+		t.Fatalf("vue d'un spectateur : %q", s.Board[5])
+	}
+	st.Finished = true
+	if r := View(st, "R"); r.Board[5][6] != "Y" {
+		t.Fatal("le plateau est dévoilé en fin de manche")
+	}
+}
+
+func TestScoutRevealsWithoutPassingTurn(t *testing.T) {
+	st := newFog()
+	playAll(t, &st, Move{Col: 0}, Move{Col: 6})
+	playAll(t, &st, Move{Kind: MoveScout, Row: 5, Col: 5})
+	if st.Next != "R" || st.Scouts["R"] != 0 {
+		t.Fatalf("après l'éclaireur : next=%s scouts=%v", st.Next, st.Scouts)
+	}
+	if got := View(st, "R").Board[5][6]; got != "Y" {
+		t.Fatalf("case voisine de l'éclaireur : %q", got)
+	}
+	if y := View(st, "Y"); y.Revealed["R"] != nil {
+		t.Fatal("Y voit les cases dévoilées par R")
+	}
+	if _, err := Play(&st, Move{Kind: MoveScout, Row: 0, Col: 0}); err != ErrIllegalMove {
+		t.Fatalf("plus d'éclaireur : err=%v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package game
+
+import "math/rand"
+
+// Conditions de fin propres à certaines variantes à gravité
+const (
+	GoalMisere = "misere" // Celui qui aligne WinLength pions perd
+	GoalExact  = "exact"  // Seul un alignement d'exactement WinLength pions gagne
+	GoalLines  = "lignes" // Plateau rempli, le joueur qui a le plus d'alignements gagne
+)
+
+// goalRules : Puissance 4 avec gravité dont seule la condition de fin change. Le nom de la
+// variante est celui de la condition (GoalMisere, GoalExact, GoalLines).
+type goalRules struct{ dropRules }
+
+// PlayerLimited : variante jouable par un nombre de joueurs limité
+type PlayerLimited interface {
+	MaxPlayers() int
+}
+
+// MaxPlayers : en misère, le perdant désigne le vainqueur, ce qui suppose deux joueurs
+func (g goalRules) MaxPlayers() int {
+	if g.name == GoalMisere {
+		return 2
+	}
+	return len(PlayerTokens)
+}
+
+func (g goalRules) Init(st *GameState, rng *rand.Rand) {
+	st.Scores = nil
+	if g.name == GoalLines {
+		st.Scores = CountLines(*st)
+	}
+}
+
+func (g goalRules) Apply(st *GameState, m Move) (Outcome, error) {
+	out, err := g.dropRules.Apply(st, m)
+	if err == nil && g.name == GoalLines {
+		st.Scores = CountLines(*st)
+	}
+	return out, err
+}
+
+func (g goalRules) Terminal(st GameState) bool {
+	if g.name == GoalLines {
+		return BoardFull(st.Board, st.Rows, st.Cols)
+	}
+	return g.Result(st) != "" || BoardFull(st.Board, st.Rows, st.Cols)
+}
+
+func (g goalRules) Result(st GameState) string {
+	switch g.name {
+	case GoalMisere:
+		if loser := WinnerWithLength(st.Board, st.Rows, st.Cols, st.WinLength); loser != "" {
+			return Opponent(loser)
+		}
+		return ""
+	case GoalExact:
+		return WinnerExactLength(st.Board, st.Rows, st.Cols, st.WinLength)
+	}
+	// Plus d'alignements : une égalité en tête est une nulle
+	best, winner := 0, ""
+	for p, n := range CountLines(st) {
+		switch {
+		case n > best:
+			best, winner = n, p
+		case n == best:
+			winner = ""
+		}
+	}
+	return winner
+}
+
+// CountLines compte pour chaque joueur les fenêtres de WinLength cases qu'il occupe entièrement
+// (un alignement de cinq pions compte double quand WinLength vaut 4)
+func CountLines(st GameState) map[string]int {
+	lines := make(map[string]int)
+	for _, p := range PlayersOf(st) {
+		lines[p] = 0
+	}
+	n := st.WinLength
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			p := st.Board[r][c]
+			if !IsDisc(p) {
+				continue
+			}
+			for _, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+				er, ec := r+(n-1)*d[0], c+(n-1)*d[1]
+				if er < 0 || er >= st.Rows || ec < 0 || ec >= st.Cols {
+					continue
+				}
+				full := true
+				for i := 1; i < n && full; i++ {
+					full = st.Board[r+i*d[0]][c+i*d[1]] == p
+				}
+				if full {
+					lines[p]++
+				}
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func newGoal(goal string) GameState {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: goal}
+	VariantOf(st).Init(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func TestMisereLineLoses(t *testing.T) {
+	st := newGoal(GoalMisere)
+	for i := 0; i < 3; i++ {
+		playAll(t, &st, Move{Col: 0}, Move{Col: 1 + i%2*5})
+	}
+	playAll(t, &st, Move{Col: 0})
+	if !st.Finished || st.Winner != "Y" {
+		t.Fatalf("R aligne quatre pions et perd : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestExactLength(t *testing.T) {
+	// Rangée du bas R R _ R R : R joue au milieu et aligne cinq pions, ce qui ne gagne pas
+	st := newGoal(GoalExact)
+	for _, c := range []int{0, 1, 3, 4} {
+		st.Board[5][c] = "R"
+		st.Board[4][c] = "Y"
+	}
+	playAll(t, &st, Move{Col: 2})
+	if st.Finished {
+		t.Fatalf("un alignement de cinq pions ne gagne pas : winner=%q", st.Winner)
+	}
+	// Y complète exactement quatre pions sur la deuxième rangée (colonnes 1 à 4)
+	st.Board[4][0] = ""
+	st.Board[5][0] = "Y"
+	playAll(t, &st, Move{Col: 2})
+	if !st.Finished || st.Winner != "Y" {
+		t.Fatalf("quatre pions exactement : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestMostLinesScores(t *testing.T) {
+	st := newGoal(GoalLines)
+	if st.Scores["R"] != 0 || st.Scores["Y"] != 0 {
+		t.Fatalf("scores initiaux : %v", st.Scores)
+	}
+	for i := 0; i < 3; i++ {
+		playAll(t, &st, Move{Col: 0}, Move{Col: 1})
+	}
+	playAll(t, &st, Move{Col: 0})
+	if st.Finished || st.Scores["R"] != 1 || st.Scores["Y"] != 0 {
+		t.Fatalf("un alignement ne termine pas la partie : finished=%v scores=%v", st.Finished, st.Scores)
+	}
+
+	// Dernière case du plateau 4x4 : R a la rangée du bas, Y aucun alignement
+	st = GameState{Rows: 4, Cols: 4, WinLength: 4, Next: "Y", Variant: GoalLines}
+	for r, row := range [][]string{{"Y", "R", "Y", ""}, {"R", "Y", "R", "Y"}, {"Y", "R", "Y", "R"}, {"R", "R", "R", "R"}} {
+		copy(st.Board[r][:], row)
+	}
+	playAll(t, &st, Move{Col: 3})
+	if !st.Finished || st.Winner != "R" || st.Scores["R"] != 1 {
+		t.Fatalf("plateau plein : finished=%v winner=%q scores=%v", st.Finished, st.Winner, st.Scores)
+	}
+}
+
+func TestBestMoveGoals(t *testing.T) {
+	// Misère : R évite de compléter sa colonne 2
+	st := newGoal(GoalMisere)
+	for r := 3; r < 6; r++ {
+		st.Board[r][2] = "R"
+		st.Board[r][5] = "Y"
+	}
+	if got := BestMove(st, 200*time.Millisecond); got == 2 {
+		t.Fatal("misère : l'IA aligne quatre pions")
+	}
+
+	// Lignes : R complète l'alignement de la rangée du bas
+	st = newGoal(GoalLines)
+	st.Board[5][0], st.Board[5][1], st.Board[5][2] = "R", "R", "R"
+	st.Board[4][0], st.Board[4][1], st.Board[4][2] = "Y", "Y", "R"
+	st.Board[3][0] = "Y"
+	if got := BestMove(st, 200*time.Millisecond); got != 3 {
+		t.Fatalf("lignes : BestMove = %d, attendu 3", got)
+	}
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Coups de l'ouverture swap2
+const (
+	MoveSwap   = "swap"   // Échanger les couleurs des pions posés (prendre l'autre couleur)
+	MoveExtend = "extend" // Poser deux pions de plus et laisser l'adversaire choisir
+	MoveKeep   = "keep"   // Garder sa couleur après les deux pions supplémentaires
+)
+
+// Phases de l'ouverture swap2 (GameState.Opening)
+const (
+	openingSwap2  = "swap2"        // R pose trois pions : noir, blanc, noir
+	openingChoice = "swap2-choice" // Y échange, pose un pion blanc ou choisit extend
+	openingExtend = "swap2-extend" // Y pose un pion noir puis un pion blanc
+	openingFinal  = "swap2-final"  // R échange ou garde sa couleur
+)
+
+// gomoku : pas de gravité, le pion se pose sur n'importe quelle case vide, par défaut sur
+// un plateau 15x15 avec cinq pions à aligner. Options : exact=1 (un alignement de plus de
+// WinLength pions ne gagne pas), opening=swap2 (ouverture swap2, R joue les noirs).
+type gomoku struct{}
+
+func (g gomoku) Name() string { return "gomoku" }
+
+func (g gomoku) DefaultSize() (rows, cols, winLength int) { return 15, 15, 5 }
+
+func (g gomoku) Init(st *GameState, rng *rand.Rand) {
+	st.Opening = ""
+	if st.Options["opening"] == "swap2" {
+		st.Opening = openingSwap2
+	}
+}
+
+func (g gomoku) LegalMoves(st GameState) []Move {
+	var moves []Move
+	switch st.Opening {
+	case openingChoice:
+		moves = append(moves, Move{Kind: MoveSwap}, Move{Kind: MoveExtend})
+	case openingFinal:
+		return []Move{{Kind: MoveSwap}, {Kind: MoveKeep}}
+	}
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if st.Board[r][c] == "" {
+				moves = append(moves, Move{Row: r, Col: c})
+			}
+		}
+	}
+	return moves
+}
+
+func (g gomoku) Apply(st *GameState, m Move) (Outcome, error) {
+	switch m.Kind {
+	case MoveDrop:
+		if st.Opening == openingFinal || m.Row < 0 || m.Row >= st.Rows || m.Col < 0 || m.Col >= st.Cols || st.Board[m.Row][m.Col] != "" {
+			return Outcome{}, ErrIllegalMove
+		}
+	case MoveSwap:
+		if st.Opening != openingChoice && st.Opening != openingFinal {
+			return Outcome{}, ErrIllegalMove
+		}
+		swapColors(st)
+		// Après l'échange, R a les blancs (un pion de moins que les noirs) et joue
+		st.Opening = ""
+		return Outcome{Next: "R"}, nil
+	case MoveExtend:
+		if st.Opening != openingChoice {
+			return Outcome{}, ErrIllegalMove
+		}
+		st.Opening = openingExtend
+		return Outcome{Next: st.Next}, nil
+	case MoveKeep:
+		if st.Opening != openingFinal {
+			return Outcome{}, ErrIllegalMove
+		}
+		st.Opening = ""
+		return Outcome{Next: "Y"}, nil
+	default:
+		return Outcome{}, ErrIllegalMove
+	}
+
+	out := Outcome{Row: m.Row, Col: m.Col}
+	color := st.Next
+	switch st.Opening {
+	case openingSwap2, openingExtend:
+		// Pions de l'ouverture posés dans l'ordre noir, blanc, noir (puis noir, blanc)
+		stones := countStones(*st)
+		color = [5]string{"R", "Y", "R", "R", "Y"}[min(stones, 4)]
+		out.Next = st.Next
+		switch {
+		case st.Opening == openingSwap2 && stones == 2:
+			st.Opening, out.Next = openingChoice, "Y"
+		case st.Opening == openingExtend && stones == 4:
+			st.Opening, out.Next = openingFinal, "R"
+		}
+	case openingChoice:
+		st.Opening = "" // Y garde les blancs et pose son pion
+	}
+	st.Board[m.Row][m.Col] = color
+	return out, nil
+}
+
+func (g gomoku) Terminal(st GameState) bool {
+	return g.Result(st) != "" || BoardFull(st.Board, st.Rows, st.Cols)
+}
+
+func (g gomoku) Result(st GameState) string {
+	if exact, _ := strconv.ParseBool(st.Options["exact"]); exact {
+		return WinnerExactLength(st.Board, st.Rows, st.Cols, st.WinLength)
+	}
+	return WinnerWithLength(st.Board, st.Rows, st.Cols, st.WinLength)
+}
+
+// FormatMove : "ligne,colonne" pour poser un pion, sinon le nom du coup d'ouverture
+func (g gomoku) FormatMove(m Move) string {
+	if m.Kind != MoveDrop {
+		return m.Kind
+	}
+	return fmt.Sprintf("%d,%d", m.Row, m.Col)
+}
+
+func (g gomoku) ParseMove(s string) (Move, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case MoveSwap, MoveExtend, MoveKeep:
+		return Move{Kind: s}, nil
+	}
+	row, col, ok := strings.Cut(s, ",")
+	r, err1 := strconv.Atoi(strings.TrimSpace(row))
+	c, err2 := strconv.Atoi(strings.TrimSpace(col))
+	if !ok || err1 != nil || err2 != nil {
+		return Move{}, fmt.Errorf("case invalide : %q", s)
+	}
+	return Move{Row: r, Col: c}, nil
+}
+
+// swapColors échange les pions rouges et jaunes du plateau
+func swapColors(st *GameState) {
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if p := st.Board[r][c]; p == "R" || p == "Y" {
+				st.Board[r][c] = Opponent(p)
+			}
+		}
+	}
+}
+
+func countStones(st GameState) int {
+	n := 0
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if IsDisc(st.Board[r][c]) {
+				n++
+			}
+		}
+	}
+	return n
+}
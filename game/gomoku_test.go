@@ -0,0 +1,99 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newGomoku(opts Options) GameState {
+	st := GameState{Rows: 15, Cols: 15, WinLength: 5, Next: "R", Variant: "gomoku", Options: opts}
+	VariantOf(st).Init(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func playAll(t *testing.T, st *GameState, moves ...Move) {
+	t.Helper()
+	for _, m := range moves {
+		if _, err := Play(st, m); err != nil {
+			t.Fatalf("%+v : %v", m, err)
+		}
+	}
+}
+
+func TestGomokuPlacesWithoutGravity(t *testing.T) {
+	st := newGomoku(nil)
+	playAll(t, &st, Move{Row: 7, Col: 7})
+	if st.Board[7][7] != "R" || st.Board[14][7] != "" || st.Next != "Y" {
+		t.Fatalf("pion mal placé : next=%s", st.Next)
+	}
+	if _, err := Play(&st, Move{Row: 7, Col: 7}); err != ErrIllegalMove {
+		t.Fatalf("case occupée acceptée : %v", err)
+	}
+	if _, err := Play(&st, Move{Row: 15, Col: 0}); err != ErrIllegalMove {
+		t.Fatalf("case hors plateau acceptée : %v", err)
+	}
+}
+
+func TestGomokuExactFive(t *testing.T) {
+	// R pose 0..3 puis 5 sur la ligne 0, Y joue sur la ligne 10 ; le dernier pion (colonne 4)
+	// fait un alignement de six
+	moves := []Move{}
+	for i, c := range []int{0, 1, 2, 3, 5} {
+		moves = append(moves, Move{Row: 0, Col: c}, Move{Row: 10, Col: 2 * i})
+	}
+	moves = append(moves, Move{Row: 0, Col: 4})
+
+	st := newGomoku(nil)
+	playAll(t, &st, moves...)
+	if !st.Finished || st.Winner != "R" {
+		t.Fatalf("six pions alignés doivent gagner sans exact : %+v", st.Winner)
+	}
+
+	st = newGomoku(Options{"exact": "1"})
+	playAll(t, &st, moves...)
+	if st.Finished {
+		t.Fatal("exact=1 : un alignement de six ne gagne pas")
+	}
+	playAll(t, &st, Move{Row: 11, Col: 0}, Move{Row: 5, Col: 0}, Move{Row: 11, Col: 1})
+	for c := 1; c <= 4; c++ {
+		playAll(t, &st, Move{Row: 5, Col: c})
+		if c < 4 {
+			playAll(t, &st, Move{Row: 12, Col: c})
+		}
+	}
+	if !st.Finished || st.Winner != "R" {
+		t.Fatalf("exact=1 : cinq pions alignés doivent gagner : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestGomokuSwap2(t *testing.T) {
+	st := newGomoku(Options{"opening": "swap2"})
+	playAll(t, &st, Move{Row: 7, Col: 7}, Move{Row: 7, Col: 8}, Move{Row: 8, Col: 7})
+	if st.Board[7][8] != "Y" || st.Next != "Y" || st.Opening != openingChoice {
+		t.Fatalf("après trois pions : next=%s opening=%s", st.Next, st.Opening)
+	}
+
+	// Y prend les noirs : les couleurs sont échangées et R (les blancs) joue
+	swapped := st
+	playAll(t, &swapped, Move{Kind: MoveSwap})
+	if swapped.Board[7][7] != "Y" || swapped.Board[7][8] != "R" || swapped.Next != "R" || swapped.Opening != "" {
+		t.Fatalf("échange : next=%s opening=%s", swapped.Next, swapped.Opening)
+	}
+
+	// Y ajoute deux pions, puis R garde les noirs : Y joue
+	playAll(t, &st, Move{Kind: MoveExtend}, Move{Row: 6, Col: 6}, Move{Row: 6, Col: 7})
+	if st.Board[6][6] != "R" || st.Board[6][7] != "Y" || st.Next != "R" || st.Opening != openingFinal {
+		t.Fatalf("après cinq pions : next=%s opening=%s", st.Next, st.Opening)
+	}
+	if _, err := Play(&st, Move{Row: 0, Col: 0}); err != ErrIllegalMove {
+		t.Fatalf("pion posé au lieu du choix de couleur : %v", err)
+	}
+	playAll(t, &st, Move{Kind: MoveKeep})
+	if st.Next != "Y" || st.Opening != "" {
+		t.Fatalf("garder : next=%s opening=%s", st.Next, st.Opening)
+	}
+	playAll(t, &st, Move{Row: 0, Col: 0})
+	if st.Board[0][0] != "Y" || st.Next != "R" {
+		t.Fatalf("jeu normal après l'ouverture : next=%s", st.Next)
+	}
+}
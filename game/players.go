@@ -0,0 +1,74 @@
+package game
+
+// Neutral : pion d'un joueur sorti de la partie (règle d'élimination), qui ne compte pour personne
+const Neutral = "X"
+
+// PlayerTokens : couleurs des joueurs dans l'ordre de jeu (rouge, jaune, vert, bleu)
+var PlayerTokens = []string{"R", "Y", "G", "B"}
+
+// PlayersOf retourne les joueurs de la partie dans l'ordre de jeu (R et Y par défaut)
+func PlayersOf(st GameState) []string {
+	if len(st.Players) > 0 {
+		return st.Players
+	}
+	return PlayerTokens[:2]
+}
+
+// Active retourne les joueurs encore en jeu, c'est-à-dire pas encore classés
+func Active(st GameState) []string {
+	var active []string
+	for _, p := range PlayersOf(st) {
+		if !ranked(st, p) {
+			active = append(active, p)
+		}
+	}
+	return active
+}
+
+func ranked(st GameState, token string) bool {
+	return contains(st.Ranking, token)
+}
+
+// IsDisc indique si la case contient le pion d'un joueur encore en jeu : ni vide, ni pion
+// neutralisé, ni case bloquée ou trou d'un modèle de plateau
+func IsDisc(cell string) bool {
+	return contains(PlayerTokens, cell)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// NextPlayer retourne le joueur encore en jeu qui suit token dans l'ordre de jeu
+func NextPlayer(st GameState, token string) string {
+	players := PlayersOf(st)
+	i := 0
+	for j, p := range players {
+		if p == token {
+			i = j
+		}
+	}
+	for k := 1; k <= len(players); k++ {
+		if p := players[(i+k)%len(players)]; !ranked(st, p) {
+			return p
+		}
+	}
+	return token
+}
+
+// eliminate classe winner et neutralise ses pions : il quitte la partie, les autres continuent
+func eliminate(st *GameState, winner string) {
+	st.Ranking = append(st.Ranking, winner)
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if st.Board[r][c] == winner {
+				st.Board[r][c] = Neutral
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newFreeForAll(players int, elimination bool) GameState {
+	st := GameState{Rows: 7, Cols: 9, WinLength: 4, Next: "R", Variant: "classique",
+		Players: PlayerTokens[:players], Elimination: elimination}
+	VariantOf(st).Init(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func TestNextPlayerRotation(t *testing.T) {
+	st := newFreeForAll(3, false)
+	for _, want := range []string{"R", "Y", "G", "R"} {
+		if st.Next != want {
+			t.Fatalf("au trait %s, attendu %s", st.Next, want)
+		}
+		playAll(t, &st, Move{Col: 0})
+	}
+	st.Ranking = []string{"G"}
+	if got := NextPlayer(st, "Y"); got != "R" {
+		t.Fatalf("un joueur classé ne joue plus : %s", got)
+	}
+}
+
+func TestFirstToConnectWins(t *testing.T) {
+	st := newFreeForAll(3, false)
+	// Y aligne quatre pions dans la colonne 1 avant que G n'aligne les siens dans la colonne 0
+	for i := 0; i < 4; i++ {
+		playAll(t, &st, Move{Col: 2 + i%2*6}, Move{Col: 1})
+		if i < 3 {
+			playAll(t, &st, Move{Col: 0})
+		}
+	}
+	if !st.Finished || st.Winner != "Y" {
+		t.Fatalf("finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestEliminationRanksPlayers(t *testing.T) {
+	st := newFreeForAll(3, true)
+	// Même partie : Y aligne en premier, la partie continue entre R et G
+	for i := 0; i < 4; i++ {
+		playAll(t, &st, Move{Col: 2 + i%2*6}, Move{Col: 1})
+		if i < 3 {
+			playAll(t, &st, Move{Col: 0})
+		}
+	}
+	if st.Finished || len(st.Ranking) != 1 || st.Ranking[0] != "Y" {
+		t.Fatalf("après le premier alignement : finished=%v ranking=%v", st.Finished, st.Ranking)
+	}
+	if st.Board[6][1] != Neutral || st.Next != "G" {
+		t.Fatalf("pions de Y non neutralisés ou mauvais trait : %q next=%s", st.Board[6][1], st.Next)
+	}
+	// G complète sa colonne : il ne reste que R, la partie est finie et Y la gagne
+	playAll(t, &st, Move{Col: 0})
+	if !st.Finished || st.Winner != "Y" || len(st.Ranking) != 2 || st.Ranking[1] != "G" {
+		t.Fatalf("fin de partie : finished=%v winner=%q ranking=%v", st.Finished, st.Winner, st.Ranking)
+	}
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Types de coups
+const (
+	MoveDrop = ""    // Faire tomber un pion (par défaut)
+	MovePop  = "pop" // PopOut : retirer un de ses pions de la rangée du bas
+)
+
+// repetitionLimit : nombre d'apparitions d'une même position qui rend la manche nulle
+const repetitionLimit = 3
+
+// popout : à son tour, on peut aussi retirer un de ses pions de la rangée du bas, la
+// colonne descend d'une case. Si un retrait aligne les pions des deux joueurs, celui qui
+// a joué gagne (option both=draw : nulle). Une position répétée trois fois est nulle.
+type popout struct{ dropRules }
+
+func (p popout) Init(st *GameState, rng *rand.Rand) {
+	st.Seen = map[string]int{positionKey(*st, st.Next): 1}
+}
+
+func (p popout) LegalMoves(st GameState) []Move {
+	moves := p.dropRules.LegalMoves(st)
+	for c := 0; c < st.Cols; c++ {
+		if st.Board[st.Rows-1][c] == st.Next {
+			moves = append(moves, Move{Kind: MovePop, Col: c})
+		}
+	}
+	return moves
+}
+
+func (p popout) Apply(st *GameState, m Move) (Outcome, error) {
+	var out Outcome
+	switch m.Kind {
+	case MoveDrop:
+		o, err := p.dropRules.Apply(st, m)
+		if err != nil {
+			return o, err
+		}
+		out = o
+	case MovePop:
+		if m.Col < 0 || m.Col >= st.Cols || st.Board[st.Rows-1][m.Col] != st.Next {
+			return Outcome{}, ErrIllegalMove
+		}
+		for r := st.Rows - 1; r > 0; r-- {
+			st.Board[r][m.Col] = st.Board[r-1][m.Col]
+		}
+		st.Board[0][m.Col] = ""
+		out = Outcome{Row: st.Rows - 1, Col: m.Col}
+	default:
+		return Outcome{}, ErrIllegalMove
+	}
+
+	// Copie avant écriture : des copies de l'état peuvent partager la table
+	seen := make(map[string]int, len(st.Seen)+1)
+	for k, v := range st.Seen {
+		seen[k] = v
+	}
+	seen[positionKey(*st, NextPlayer(*st, st.Next))]++
+	st.Seen = seen
+	return out, nil
+}
+
+// lines retourne les joueurs ayant un alignement de WinLength pions
+func lines(st GameState) map[string]bool {
+	found := map[string]bool{}
+	for _, player := range PlayersOf(st) {
+		for r := 0; r < st.Rows && !found[player]; r++ {
+			for c := 0; c < st.Cols && !found[player]; c++ {
+				if st.Board[r][c] == player && LineThrough(st.Board, st.Rows, st.Cols, st.WinLength, r, c) {
+					found[player] = true
+				}
+			}
+		}
+	}
+	return found
+}
+
+func (p popout) Terminal(st GameState) bool {
+	// Appelée juste après le coup : st.Next est encore le joueur qui vient de jouer
+	if len(lines(st)) > 0 || st.Seen[positionKey(st, NextPlayer(st, st.Next))] >= repetitionLimit {
+		return true
+	}
+	// Plateau plein : la partie continue tant que le joueur suivant peut retirer un pion
+	next := st
+	next.Next = NextPlayer(st, st.Next)
+	return len(p.LegalMoves(next)) == 0
+}
+
+func (p popout) Result(st GameState) string {
+	found := lines(st)
+	if len(found) > 1 {
+		if st.Options["both"] == "draw" {
+			return ""
+		}
+		return st.Next // Le trait ne passe pas en fin de manche : c'est le joueur qui a retiré le pion
+	}
+	for player := range found {
+		return player
+	}
+	return ""
+}
+
+// FormatMove : "3" pour faire tomber un pion, "p3" pour retirer celui du bas de la colonne 3
+func (p popout) FormatMove(m Move) string {
+	if m.Kind == MovePop {
+		return "p" + strconv.Itoa(m.Col)
+	}
+	return strconv.Itoa(m.Col)
+}
+
+func (p popout) ParseMove(s string) (Move, error) {
+	s = strings.TrimSpace(s)
+	if rest, ok := strings.CutPrefix(s, "p"); ok {
+		col, err := strconv.Atoi(rest)
+		if err != nil {
+			return Move{}, fmt.Errorf("retrait invalide : %q", s)
+		}
+		return Move{Kind: MovePop, Col: col}, nil
+	}
+	return p.dropRules.ParseMove(s)
+}
+
+// positionKey identifie une position : plateau et joueur au trait toMove
+func positionKey(st GameState, toMove string) string {
+	var b strings.Builder
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			switch st.Board[r][c] {
+			case "":
+				b.WriteByte('.')
+			default:
+				b.WriteString(st.Board[r][c])
+			}
+		}
+	}
+	b.WriteString(toMove)
+	return b.String()
+}
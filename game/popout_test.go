@@ -0,0 +1,113 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func newPopout(rows, cols int, opts Options) GameState {
+	st := GameState{Rows: rows, Cols: cols, WinLength: 4, Next: "R", Variant: "popout", Options: opts}
+	VariantOf(st).Init(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func TestPopoutPopShiftsColumn(t *testing.T) {
+	st := newPopout(6, 7, nil)
+	for _, m := range []Move{{Col: 2}, {Col: 2}, {Col: 2}} {
+		if _, err := Play(&st, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Colonne 2 de bas en haut : R, Y, R ; Y ne peut pas retirer le pion rouge
+	if _, err := Play(&st, Move{Kind: MovePop, Col: 2}); err != ErrIllegalMove {
+		t.Fatalf("retrait d'un pion adverse accepté : %v", err)
+	}
+	if _, err := Play(&st, Move{Col: 0}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Play(&st, Move{Kind: MovePop, Col: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Row != 5 || st.Board[5][2] != "Y" || st.Board[4][2] != "R" || st.Board[3][2] != "" {
+		t.Fatalf("colonne après retrait : %q %q %q", st.Board[5][2], st.Board[4][2], st.Board[3][2])
+	}
+	if st.Next != "Y" {
+		t.Fatalf("le trait doit passer après un retrait, next=%s", st.Next)
+	}
+}
+
+func TestPopoutBothLines(t *testing.T) {
+	// Retirer le pion rouge du bas de la colonne 0 aligne les deux couleurs
+	board := func() GameState {
+		st := newPopout(6, 7, nil)
+		for i, p := range []string{"R", "Y", "R", "Y"} {
+			st.Board[5-i][0] = p
+		}
+		for c := 1; c <= 3; c++ {
+			st.Board[5][c], st.Board[4][c], st.Board[3][c] = "Y", "R", "Y"
+		}
+		return st
+	}
+	st := board()
+	if _, err := Play(&st, Move{Kind: MovePop, Col: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Finished || st.Winner != "R" {
+		t.Fatalf("le joueur qui retire doit gagner : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+
+	st = board()
+	st.Options = Options{"both": "draw"}
+	if _, err := Play(&st, Move{Kind: MovePop, Col: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Finished || st.Winner != "" {
+		t.Fatalf("both=draw : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestPopoutRepetitionDraw(t *testing.T) {
+	st := newPopout(6, 7, nil)
+	cycle := []Move{{Col: 0}, {Col: 1}, {Kind: MovePop, Col: 0}, {Kind: MovePop, Col: 1}}
+	for round := 0; round < 2; round++ {
+		for _, m := range cycle {
+			if st.Finished {
+				t.Fatalf("partie finie trop tôt au tour %d", round)
+			}
+			if _, err := Play(&st, m); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if !st.Finished || st.Winner != "" {
+		t.Fatalf("triple répétition non détectée : finished=%v winner=%q", st.Finished, st.Winner)
+	}
+}
+
+func TestPopoutFullBoardContinues(t *testing.T) {
+	st := newPopout(4, 4, nil)
+	rows := [][]string{{"R", "R", "Y", "Y"}, {"Y", "Y", "R", "R"}, {"R", "R", "Y", "Y"}, {"Y", "Y", "R", "R"}}
+	for r, row := range rows {
+		copy(st.Board[r][:], row)
+	}
+	v := VariantOf(st)
+	if v.Terminal(st) {
+		t.Fatal("plateau plein : Y peut encore retirer un pion")
+	}
+	st.Next = "Y"
+	moves := v.LegalMoves(st)
+	if len(moves) != 2 || moves[0] != (Move{Kind: MovePop, Col: 0}) || moves[1] != (Move{Kind: MovePop, Col: 1}) {
+		t.Fatalf("coups légaux : %+v", moves)
+	}
+}
+
+func TestPopoutNotation(t *testing.T) {
+	v, _ := Lookup("popout")
+	for _, m := range []Move{{Col: 3}, {Kind: MovePop, Col: 5}} {
+		got, err := v.ParseMove(v.FormatMove(m))
+		if err != nil || got != m {
+			t.Fatalf("%+v : %+v, %v", m, got, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// Cases particulières posées par un modèle de plateau
+const (
+	Blocked = "#" // Case bloquée pour toute la partie : les pions se posent dessus
+	Hole    = "-" // Trou, hors du plateau : les pions passent au travers
+)
+
+// Template : modèle de plateau avec sa forme, ses obstacles et ses pions posés d'avance.
+// Format JSON d'import/export :
+//
+//	{"name": "Croix", "winLength": 4, "cells": ["--...--", "--...--", ".......", ...]}
+//
+// Une chaîne par rangée, de haut en bas : '.' case vide, '#' case bloquée, '-' trou,
+// 'R', 'Y', 'G' ou 'B' pion posé d'avance.
+type Template struct {
+	Name      string   `json:"name"`
+	WinLength int      `json:"winLength,omitempty"` // Absente : longueur de la partie
+	Cells     []string `json:"cells"`
+}
+
+// Fixed indique si la case est posée par le modèle pour toute la partie (case bloquée ou trou)
+func Fixed(cell string) bool {
+	return cell == Blocked || cell == Hole
+}
+
+var errTemplateShape = errors.New("Modèle invalide : rangées de même longueur, 15x15 au plus")
+
+// ParseTemplate lit un modèle au format JSON et vérifie sa forme
+func ParseTemplate(data []byte) (*Template, error) {
+	var t Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("Modèle illisible : %v", err)
+	}
+	// Une longueur nulle écrite dans le modèle est une erreur, pas la longueur de la partie
+	var given struct {
+		WinLength *int `json:"winLength"`
+	}
+	if json.Unmarshal(data, &given) == nil && given.WinLength != nil && *given.WinLength == 0 {
+		t.WinLength = -1
+	}
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (t *Template) check() error {
+	rows, cols := t.Size()
+	if rows == 0 || rows > 15 || cols == 0 || cols > 15 {
+		return errTemplateShape
+	}
+	free := false
+	for _, row := range t.Cells {
+		if len(row) != cols {
+			return errTemplateShape
+		}
+		for _, ch := range row {
+			switch s := string(ch); {
+			case s == ".":
+				free = true
+			case s == Blocked || s == Hole || contains(PlayerTokens, s):
+			default:
+				return fmt.Errorf("Modèle invalide : case %q inconnue", s)
+			}
+		}
+	}
+	if !free {
+		return errors.New("Modèle invalide : aucune case libre")
+	}
+	// Même règle que le paramètre win de la création de partie
+	if t.WinLength != 0 && (t.WinLength < 2 || t.WinLength > max(rows, cols)) {
+		return fmt.Errorf("Modèle invalide : longueur d'alignement entre 2 et %d", max(rows, cols))
+	}
+	return nil
+}
+
+// Size retourne les dimensions du plateau décrit par le modèle
+func (t *Template) Size() (rows, cols int) {
+	if len(t.Cells) == 0 {
+		return 0, 0
+	}
+	return len(t.Cells), len(t.Cells[0])
+}
+
+// Discs retourne les joueurs dont un pion est posé d'avance
+func (t *Template) Discs() []string {
+	var discs []string
+	for _, row := range t.Cells {
+		for _, ch := range row {
+			if s := string(ch); contains(PlayerTokens, s) && !contains(discs, s) {
+				discs = append(discs, s)
+			}
+		}
+	}
+	return discs
+}
+
+// apply recopie le modèle sur le plateau de st
+func (t *Template) apply(st *GameState) {
+	for r, row := range t.Cells {
+		for c, ch := range row {
+			st.Board[r][c] = ""
+			if s := string(ch); s != "." {
+				st.Board[r][c] = s
+			}
+		}
+	}
+}
+
+// Setup prépare le plateau d'une nouvelle manche : modèle de plateau éventuel, puis la variante
+func Setup(st *GameState, rng *rand.Rand) {
+	if st.Template != nil {
+		st.Template.apply(st)
+	}
+	VariantOf(*st).Init(st, rng)
+}
+
+// Templates : modèles de plateau proposés par défaut
+var Templates = []Template{
+	{Name: "Pyramide", Cells: []string{
+		"-----.-----",
+		"----...----",
+		"---.....---",
+		"--.......--",
+		"-.........-",
+		"...........",
+	}},
+	{Name: "Croix", Cells: []string{
+		"--...--",
+		"--...--",
+		".......",
+		".......",
+		".......",
+		"--...--",
+		"--...--",
+	}},
+	{Name: "Obstacles", Cells: []string{
+		".......",
+		".......",
+		"...#...",
+		".......",
+		"#.....#",
+		"..Y.R..",
+	}},
+}
+
+// LookupTemplate retourne le modèle par défaut de ce nom
+func LookupTemplate(name string) (*Template, bool) {
+	for i := range Templates {
+		if Templates[i].Name == name {
+			t := Templates[i]
+			return &t, true
+		}
+	}
+	return nil, false
+}
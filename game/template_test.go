@@ -0,0 +1,94 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func newTemplated(t *testing.T, cells ...string) GameState {
+	t.Helper()
+	tmpl := &Template{Name: "test", Cells: cells}
+	if err := tmpl.check(); err != nil {
+		t.Fatal(err)
+	}
+	rows, cols := tmpl.Size()
+	st := GameState{Rows: rows, Cols: cols, WinLength: 4, Next: "R", Template: tmpl}
+	Setup(&st, rand.New(rand.NewSource(1)))
+	return st
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(`{"name":"mini","winLength":3,"cells":["-..-","....","#RY."]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows, cols := tmpl.Size(); rows != 3 || cols != 4 || tmpl.WinLength != 3 {
+		t.Fatalf("taille %dx%d, winLength %d", rows, cols, tmpl.WinLength)
+	}
+	if discs := tmpl.Discs(); len(discs) != 2 || discs[0] != "R" || discs[1] != "Y" {
+		t.Fatalf("pions posés d'avance : %v", discs)
+	}
+	for _, bad := range []string{`{"cells":[]}`, `{"cells":["...","...."]}`, `{"cells":["..x"]}`, `{"cells":["##-"]}`, `{cells}`,
+		`{"winLength":1,"cells":["...."]}`, `{"winLength":0,"cells":["...."]}`, `{"winLength":5,"cells":["...."]}`, `{"winLength":-3,"cells":["...."]}`} {
+		if _, err := ParseTemplate([]byte(bad)); err == nil {
+			t.Errorf("%s aurait dû être refusé", bad)
+		}
+	}
+	for _, tmpl := range Templates {
+		if err := tmpl.check(); err != nil {
+			t.Errorf("%s : %v", tmpl.Name, err)
+		}
+	}
+}
+
+func TestTemplateGravity(t *testing.T) {
+	st := newTemplated(t,
+		"--.--",
+		"..#..",
+		".....",
+		"-...-",
+		"-...-",
+	)
+	// Colonne 0 : le pion passe au travers du trou du haut et s'arrête au-dessus des trous du bas
+	if r := Drop(&st, 0); r != 2 {
+		t.Fatalf("colonne 0 : ligne %d, attendu 2", r)
+	}
+	// Colonne 2 : le pion s'arrête sur la case bloquée, les cases en dessous restent vides
+	if r := Drop(&st, 2); r != 0 {
+		t.Fatalf("colonne 2 : ligne %d, attendu 0", r)
+	}
+	for _, c := range LegalColumns(st) {
+		if c == 2 {
+			t.Fatal("colonne 2 pleine jugée jouable")
+		}
+	}
+	if r := Drop(&st, 0); r != 1 || Drop(&st, 0) != -1 {
+		t.Fatalf("colonne 0 : deuxième pion en %d", r)
+	}
+	if len(LegalColumns(st)) != 3 {
+		t.Fatalf("colonnes jouables : %v", LegalColumns(st))
+	}
+}
+
+func TestTemplateBlocksLines(t *testing.T) {
+	st := newTemplated(t,
+		".......",
+		".......",
+		".......",
+		"RR#R...",
+	)
+	if st.Board[3][0] != "R" || st.Board[3][2] != Blocked {
+		t.Fatalf("modèle non appliqué : %q", st.Board[3])
+	}
+	if WinnerWithLength(st.Board, st.Rows, st.Cols, 4) != "" {
+		t.Fatal("une case bloquée interrompt l'alignement")
+	}
+	st.Next = "R"
+	if _, err := Play(&st, Move{Col: 4}); err != nil || st.Finished {
+		t.Fatalf("R R # R R ne gagne pas : finished=%v err=%v", st.Finished, err)
+	}
+	if got := BestMove(st, 50*time.Millisecond); got < 0 || got >= st.Cols {
+		t.Fatalf("BestMove = %d", got)
+	}
+}
@@ -1,14 +1,29 @@
 package game
 
 type GameState struct {
-	Board        [15][15]string `json:"board"`        // Taille max pour supporter la croissance du mode exponentiel
-	BoosterCells [15][15]string `json:"boosterCells"` // Cases spéciales contenant des boosters ("double-shot", "remove-piece", etc.)
-	Next         string         `json:"next"`
-	Winner       string         `json:"winner"`
-	Finished     bool           `json:"finished"`
-	Mode         string         `json:"mode"`
-	Rows         int            `json:"rows"`
-	Cols         int            `json:"cols"`
-	WinLength    int            `json:"winLength"`
-	Version      int            `json:"version"`
+	Board        [15][15]string      `json:"board"`        // Taille max pour supporter la croissance du mode exponentiel
+	BoosterCells [15][15]string      `json:"boosterCells"` // Cases spéciales contenant des boosters ("double-shot", "remove-piece", etc.)
+	Next         string              `json:"next"`
+	Winner       string              `json:"winner"`
+	Finished     bool                `json:"finished"`
+	Mode         string              `json:"mode"`              // Libellé du mode (ex : multi-turbo)
+	Variant      string              `json:"variant,omitempty"` // Règles (voir Register), classique si vide
+	Options      Options             `json:"options,omitempty"` // Options de la variante
+	Rows         int                 `json:"rows"`
+	Cols         int                 `json:"cols"`
+	WinLength    int                 `json:"winLength"`
+	Version      int                 `json:"version"`
+	Seen         map[string]int      `json:"seen,omitempty"`        // Positions déjà rencontrées (règle de répétition)
+	Opening      string              `json:"opening,omitempty"`     // Phase d'ouverture en cours (gomoku swap2), "" ensuite
+	Players      []string            `json:"players,omitempty"`     // Joueurs dans l'ordre de jeu (R et Y si vide)
+	Elimination  bool                `json:"elimination,omitempty"` // Le joueur aligné est classé et les autres continuent
+	Ranking      []string            `json:"ranking,omitempty"`     // Joueurs classés, dans l'ordre (élimination)
+	Scores       map[string]int      `json:"scores,omitempty"`      // Alignements par joueur (variante lignes)
+	Template     *Template           `json:"template,omitempty"`    // Modèle de plateau (forme, obstacles, pions posés d'avance)
+	WinLine      [][2]int            `json:"winLine,omitempty"`     // Cases de l'alignement gagnant (variantes Lined)
+	Scouts       map[string]int      `json:"scouts,omitempty"`      // Éclaireurs restants par joueur (brouillard)
+	Revealed     map[string][][2]int `json:"revealed,omitempty"`    // Cases dévoilées par les éclaireurs de chaque joueur
 }
+
+// Options : options d'une variante, sauvegardées avec l'état de la partie
+type Options map[string]string
@@ -0,0 +1,260 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// ErrIllegalMove : coup refusé par les règles de la variante
+var ErrIllegalMove = errors.New("Coup impossible")
+
+// Move : un coup. Les variantes à gravité n'utilisent que la colonne.
+type Move struct {
+	Kind string `json:"kind,omitempty"` // MoveDrop, MovePop, MoveSwap...
+	Row  int    `json:"row,omitempty"`  // Variantes sans gravité : ligne de la case choisie
+	Col  int    `json:"col"`
+}
+
+// Outcome : effet d'un coup joué
+type Outcome struct {
+	Row     int    // Ligne où le pion s'est posé
+	Col     int    // Colonne du pion
+	Booster string // Booster ramassé sur la case (turbo), sinon ""
+	Next    string // Joueur au trait imposé par la variante (ouverture), "" = l'adversaire
+}
+
+// Variant : règles d'une variante de jeu. Les méthodes ne modifient que l'état reçu ;
+// le trait et la fin de manche sont gérés par Play.
+type Variant interface {
+	Name() string
+	// Init prépare le plateau d'une nouvelle manche (Rows, Cols, WinLength et Options sont fixés)
+	Init(st *GameState, rng *rand.Rand)
+	// LegalMoves liste les coups possibles pour st.Next
+	LegalMoves(st GameState) []Move
+	// Apply joue m pour st.Next, sans changer le trait
+	Apply(st *GameState, m Move) (Outcome, error)
+	// Terminal indique si la manche est finie
+	Terminal(st GameState) bool
+	// Result retourne le vainqueur d'une position terminale ("" pour une nulle)
+	Result(st GameState) string
+	// FormatMove et ParseMove : notation textuelle des coups (client terminal, historique)
+	FormatMove(m Move) string
+	ParseMove(s string) (Move, error)
+}
+
+var (
+	registry = make(map[string]Variant)
+	order    []string
+)
+
+// Register ajoute une variante au registre ; un nom déjà pris provoque une panique
+func Register(v Variant) {
+	if _, dup := registry[v.Name()]; dup {
+		panic("game: variante déjà enregistrée : " + v.Name())
+	}
+	registry[v.Name()] = v
+	order = append(order, v.Name())
+}
+
+// Sized : variante jouée par défaut sur un plateau et avec un alignement qui lui sont propres
+type Sized interface {
+	DefaultSize() (rows, cols, winLength int)
+}
+
+// Lookup retourne la variante enregistrée sous ce nom
+func Lookup(name string) (Variant, bool) {
+	v, ok := registry[name]
+	return v, ok
+}
+
+// Variants liste les noms des variantes dans l'ordre d'enregistrement
+func Variants() []string {
+	return append([]string(nil), order...)
+}
+
+// VariantOf retourne la variante de st (classique si elle est absente ou inconnue)
+func VariantOf(st GameState) Variant {
+	if v, ok := registry[st.Variant]; ok {
+		return v
+	}
+	return registry["classique"]
+}
+
+// Play joue m pour st.Next selon la variante de st, puis termine la manche ou passe le trait
+// au joueur suivant (voir NextPlayer)
+func Play(st *GameState, m Move) (Outcome, error) {
+	if st.Finished {
+		return Outcome{}, ErrIllegalMove
+	}
+	v := VariantOf(*st)
+	out, err := v.Apply(st, m)
+	if err != nil {
+		return out, err
+	}
+	switch {
+	case v.Terminal(*st):
+		winner := v.Result(*st)
+		if st.Elimination && winner != "" {
+			// Élimination : le joueur aligné prend la place suivante du classement ; la partie
+			// continue tant qu'il reste au moins deux joueurs et une position ouverte
+			eliminate(st, winner)
+			if len(Active(*st)) >= 2 && !v.Terminal(*st) {
+				st.Next = NextPlayer(*st, st.Next)
+				return out, nil
+			}
+		}
+		st.Finished = true
+		st.Winner = winner
+		if len(st.Ranking) > 0 {
+			st.Winner = st.Ranking[0]
+		}
+		if l, ok := v.(Lined); ok && winner != "" {
+			st.WinLine = l.WinningLine(*st)
+		}
+	case out.Next != "":
+		st.Next = out.Next
+	default:
+		st.Next = NextPlayer(*st, st.Next)
+	}
+	return out, nil
+}
+
+func init() {
+	Register(dropRules{name: "classique"})
+	Register(turbo{dropRules{name: "turbo"}})
+	Register(dropRules{name: "exponentiel"}) // Règles classiques ; la campagne est gérée par le serveur
+	Register(popout{dropRules{name: "popout"}})
+	Register(gomoku{})
+	Register(goalRules{dropRules{name: GoalMisere}})
+	Register(goalRules{dropRules{name: GoalExact}})
+	Register(goalRules{dropRules{name: GoalLines}})
+	Register(tore{dropRules{name: "tore"}})
+	Register(fog{dropRules{name: "brouillard"}})
+	Register(blitz{dropRules{name: "blitz"}})
+}
+
+// dropRules : Puissance 4 avec gravité, le premier alignement de WinLength pions gagne
+type dropRules struct{ name string }
+
+func (d dropRules) Name() string { return d.name }
+
+func (d dropRules) gravity() {}
+
+// Gravity indique si les pions de st tombent dans la colonne choisie : un coup se résume
+// alors à une colonne (moteurs, BestMove). Faux pour le gomoku, où l'on choisit la case.
+func Gravity(st GameState) bool {
+	_, ok := VariantOf(st).(interface{ gravity() })
+	return ok
+}
+
+func (d dropRules) Init(st *GameState, rng *rand.Rand) {}
+
+func (d dropRules) LegalMoves(st GameState) []Move {
+	var moves []Move
+	for _, c := range LegalColumns(st) {
+		moves = append(moves, Move{Col: c})
+	}
+	return moves
+}
+
+func (d dropRules) Apply(st *GameState, m Move) (Outcome, error) {
+	if m.Kind != MoveDrop {
+		return Outcome{}, ErrIllegalMove
+	}
+	row := Drop(st, m.Col)
+	if row < 0 {
+		return Outcome{}, ErrIllegalMove
+	}
+	return Outcome{Row: row, Col: m.Col}, nil
+}
+
+func (d dropRules) Terminal(st GameState) bool {
+	return d.Result(st) != "" || BoardFull(st.Board, st.Rows, st.Cols)
+}
+
+func (d dropRules) Result(st GameState) string {
+	return WinnerWithLength(st.Board, st.Rows, st.Cols, st.WinLength)
+}
+
+func (d dropRules) FormatMove(m Move) string { return strconv.Itoa(m.Col) }
+
+func (d dropRules) ParseMove(s string) (Move, error) {
+	col, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return Move{}, fmt.Errorf("colonne invalide : %q", s)
+	}
+	return Move{Col: col}, nil
+}
+
+// BoosterTypes : boosters du mode turbo, dans l'ordre de placement
+var BoosterTypes = []string{"double-shot", "remove-piece", "block-column", "swap-colors", "wildcard"}
+
+// turbo : règles classiques avec des cases booster ramassées par le pion qui s'y pose.
+// L'option "boosters" fixe le nombre de cases de chaque type ("double-shot=2,wildcard=1").
+type turbo struct{ dropRules }
+
+func (t turbo) Init(st *GameState, rng *rand.Rand) {
+	st.BoosterCells = [15][15]string{}
+	counts := BoosterCounts(st.Options["boosters"])
+
+	type position struct{ row, col int }
+	var positions []position
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if st.Board[r][c] == "" { // Pas de booster sur une case d'un modèle de plateau
+				positions = append(positions, position{r, c})
+			}
+		}
+	}
+	rng.Shuffle(len(positions), func(i, j int) { positions[i], positions[j] = positions[j], positions[i] })
+
+	next := 0
+	for _, b := range BoosterTypes {
+		for i := 0; i < counts[b] && next < len(positions); i++ {
+			st.BoosterCells[positions[next].row][positions[next].col] = b
+			next++
+		}
+	}
+}
+
+func (t turbo) Apply(st *GameState, m Move) (Outcome, error) {
+	out, err := t.dropRules.Apply(st, m)
+	if err != nil {
+		return out, err
+	}
+	if b := st.BoosterCells[out.Row][out.Col]; b != "" {
+		out.Booster = b
+		st.BoosterCells[out.Row][out.Col] = ""
+	}
+	return out, nil
+}
+
+// BoosterCounts lit une répartition "type=n,..." ; sans option, deux cases de chaque type
+func BoosterCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	if s == "" {
+		for _, b := range BoosterTypes {
+			counts[b] = 2
+		}
+		return counts
+	}
+	for _, entry := range strings.Split(s, ",") {
+		name, n, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if v, err := strconv.Atoi(n); err == nil {
+			counts[name] = v
+		}
+	}
+	return counts
+}
+
+// FormatBoosterCounts est l'inverse de BoosterCounts
+func FormatBoosterCounts(counts map[string]int) string {
+	var parts []string
+	for _, b := range BoosterTypes {
+		parts = append(parts, fmt.Sprintf("%s=%d", b, counts[b]))
+	}
+	return strings.Join(parts, ",")
+}
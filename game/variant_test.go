@@ -0,0 +1,81 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRegistryHasBuiltinVariants(t *testing.T) {
+	for _, name := range []string{"classique", "turbo", "exponentiel", "popout", "gomoku", GoalMisere, GoalExact, GoalLines} {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("variante %q absente du registre", name)
+		}
+	}
+	if VariantOf(GameState{}).Name() != "classique" {
+		t.Error("un état sans variante doit suivre les règles classiques")
+	}
+}
+
+func TestGravity(t *testing.T) {
+	for _, name := range Variants() {
+		if got := Gravity(GameState{Variant: name}); got != (name != "gomoku") {
+			t.Errorf("Gravity(%s) = %v", name, got)
+		}
+	}
+}
+
+func TestPlayEndsGameOnAlignment(t *testing.T) {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: "classique"}
+	for _, col := range []int{0, 1, 0, 1, 0, 1} {
+		if _, err := Play(&st, Move{Col: col}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if st.Next != "R" || st.Finished {
+		t.Fatalf("après 6 coups : next=%s finished=%v", st.Next, st.Finished)
+	}
+	if _, err := Play(&st, Move{Col: 0}); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Finished || st.Winner != "R" {
+		t.Fatalf("alignement vertical non détecté : %+v", st)
+	}
+	if _, err := Play(&st, Move{Col: 3}); err != ErrIllegalMove {
+		t.Fatalf("coup après la fin accepté : %v", err)
+	}
+}
+
+func TestTurboBoosterCells(t *testing.T) {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: "turbo",
+		Options: Options{"boosters": "double-shot=3,wildcard=1"}}
+	v := VariantOf(st)
+	v.Init(&st, rand.New(rand.NewSource(1)))
+	counts := map[string]int{}
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if b := st.BoosterCells[r][c]; b != "" {
+				counts[b]++
+			}
+		}
+	}
+	if counts["double-shot"] != 3 || counts["wildcard"] != 1 || len(counts) != 2 {
+		t.Fatalf("répartition des boosters : %v", counts)
+	}
+
+	st.BoosterCells[5][2] = "swap-colors"
+	out, err := v.Apply(&st, Move{Col: 2})
+	if err != nil || out.Booster != "swap-colors" || st.BoosterCells[5][2] != "" {
+		t.Fatalf("booster non ramassé : %+v, %v", out, err)
+	}
+}
+
+func TestMoveNotation(t *testing.T) {
+	v := VariantOf(GameState{})
+	m, err := v.ParseMove(" 4 ")
+	if err != nil || m.Col != 4 || v.FormatMove(m) != "4" {
+		t.Fatalf("ParseMove(\" 4 \") = %+v, %v", m, err)
+	}
+	if _, err := v.ParseMove("x"); err == nil {
+		t.Fatal("notation invalide acceptée")
+	}
+}
@@ -6,12 +6,13 @@ func Winner(board [15][15]string) string {
 	return WinnerWithLength(board, 6, 7, 4)
 }
 
-// WinnerWithLength vérifie s'il y a un gagnant avec un nombre d'alignements spécifique
+// WinnerWithLength vérifie s'il y a un gagnant avec un nombre d'alignements spécifique.
+// Tout pion de joueur compte (R, Y, G, B), pas les pions neutralisés ni les cases d'un modèle.
 func WinnerWithLength(board [15][15]string, rows, cols, winLength int) string {
 	for r := 0; r < rows; r++ {
 		for c := 0; c < cols; c++ {
 			p := board[r][c]
-			if p == "" {
+			if !IsDisc(p) {
 				continue
 			}
 			// horizontal →
@@ -70,3 +71,30 @@ func WinnerWithLength(board [15][15]string, rows, cols, winLength int) string {
 	}
 	return ""
 }
+
+// WinnerExactLength : comme WinnerWithLength, mais seul un alignement d'exactement winLength
+// pions gagne (un alignement plus long ne compte pas)
+func WinnerExactLength(board [15][15]string, rows, cols, winLength int) string {
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			p := board[r][c]
+			if !IsDisc(p) {
+				continue
+			}
+			for _, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+				// On ne compte chaque alignement qu'une fois, depuis sa première case
+				if pr, pc := r-d[0], c-d[1]; pr >= 0 && pr < rows && pc >= 0 && pc < cols && board[pr][pc] == p {
+					continue
+				}
+				n := 1
+				for rr, cc := r+d[0], c+d[1]; rr >= 0 && rr < rows && cc >= 0 && cc < cols && board[rr][cc] == p; rr, cc = rr+d[0], cc+d[1] {
+					n++
+				}
+				if n == winLength {
+					return p
+				}
+			}
+		}
+	}
+	return ""
+}
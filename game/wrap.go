@@ -0,0 +1,92 @@
+package game
+
+import "strconv"
+
+// tore : Puissance 4 sur un plateau dont les bords gauche et droit se rejoignent, les
+// alignements horizontaux et diagonaux continuent de la dernière colonne à la première.
+// Option vertical=1 : les bords haut et bas se rejoignent aussi.
+type tore struct{ dropRules }
+
+// Lined : variante qui indique les cases de l'alignement gagnant (affichées en fin de manche)
+type Lined interface {
+	WinningLine(st GameState) [][2]int
+}
+
+func (t tore) Terminal(st GameState) bool {
+	return t.Result(st) != "" || BoardFull(st.Board, st.Rows, st.Cols)
+}
+
+func (t tore) Result(st GameState) string {
+	if line := t.WinningLine(st); line != nil {
+		return st.Board[line[0][0]][line[0][1]]
+	}
+	return ""
+}
+
+// WinningLine retourne les cases ([ligne, colonne]) du premier alignement trouvé, nil sans alignement
+func (t tore) WinningLine(st GameState) [][2]int {
+	for r := 0; r < st.Rows; r++ {
+		for c := 0; c < st.Cols; c++ {
+			if line := WrapLineThrough(st, r, c); line != nil {
+				return line
+			}
+		}
+	}
+	return nil
+}
+
+// Wraps retourne les bords qui se rejoignent pour la variante de st
+func Wraps(st GameState) (horizontal, vertical bool) {
+	if st.Variant != "tore" {
+		return false, false
+	}
+	v, _ := strconv.ParseBool(st.Options["vertical"])
+	return true, v
+}
+
+// WrapLineThrough retourne les cases d'un alignement d'au moins WinLength pions passant par
+// (r, c) en tenant compte des bords qui se rejoignent, nil s'il n'y en a pas
+func WrapLineThrough(st GameState, r, c int) [][2]int {
+	h, v := Wraps(st)
+	return wrapLine(&st.Board, st.Rows, st.Cols, st.WinLength, r, c, h, v)
+}
+
+func wrapLine(board *[15][15]string, rows, cols, winLength, r, c int, h, v bool) [][2]int {
+	p := board[r][c]
+	if !IsDisc(p) {
+		return nil
+	}
+	for _, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+		line := [][2]int{{r, c}}
+		rr, cc, ok := r, c, true
+		for {
+			if rr, cc, ok = wrapStep(rows, cols, rr+d[0], cc+d[1], h, v); !ok || board[rr][cc] != p || (rr == r && cc == c) {
+				break
+			}
+			line = append(line, [2]int{rr, cc})
+		}
+		// Un tour complet du plateau ne se compte qu'une fois
+		for full := ok && rr == r && cc == c; !full; {
+			if rr, cc, ok = wrapStep(rows, cols, line[0][0]-d[0], line[0][1]-d[1], h, v); !ok || board[rr][cc] != p {
+				break
+			}
+			line = append([][2]int{{rr, cc}}, line...)
+		}
+		if len(line) >= winLength {
+			return line
+		}
+	}
+	return nil
+}
+
+// wrapStep ramène (r, c) sur le plateau par les bords qui se rejoignent ; ok est faux si la
+// case est au-delà d'un bord qui ne se rejoint pas
+func wrapStep(rows, cols, r, c int, horizontal, vertical bool) (int, int, bool) {
+	if horizontal {
+		c = (c + cols) % cols
+	}
+	if vertical {
+		r = (r + rows) % rows
+	}
+	return r, c, r >= 0 && r < rows && c >= 0 && c < cols
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func newTore(vertical bool) GameState {
+	st := GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Variant: "tore"}
+	if vertical {
+		st.Options = Options{"vertical": "1"}
+	}
+	return st
+}
+
+func TestToreHorizontalWrap(t *testing.T) {
+	// Rangée du bas : R en colonnes 5, 6 et 0 ; la colonne 1 complète l'alignement par le bord
+	st := newTore(false)
+	for _, c := range []int{5, 6, 0} {
+		st.Board[5][c] = "R"
+		st.Board[4][c] = "Y"
+	}
+	st.Board[5][3] = "Y"
+	if WinnerWithLength(st.Board, st.Rows, st.Cols, st.WinLength) != "" {
+		t.Fatal("aucun alignement sans les bords")
+	}
+	playAll(t, &st, Move{Col: 1})
+	if !st.Finished || st.Winner != "R" {
+		t.Fatalf("finished=%v winner=%q", st.Finished, st.Winner)
+	}
+	want := [][2]int{{5, 5}, {5, 6}, {5, 0}, {5, 1}}
+	if len(st.WinLine) != len(want) {
+		t.Fatalf("alignement gagnant %v, attendu %v", st.WinLine, want)
+	}
+	for i := range want {
+		if st.WinLine[i] != want[i] {
+			t.Fatalf("alignement gagnant %v, attendu %v", st.WinLine, want)
+		}
+	}
+}
+
+func TestToreVerticalOption(t *testing.T) {
+	// Colonne 0, de haut en bas : R R Y Y R R
+	for _, vertical := range []bool{false, true} {
+		st := newTore(vertical)
+		for r, p := range []string{"R", "R", "Y", "Y", "R", "R"} {
+			st.Board[r][0] = p
+		}
+		if got := WrapLineThrough(st, 0, 0) != nil; got != vertical {
+			t.Fatalf("vertical=%v : alignement par le haut et le bas = %v", vertical, got)
+		}
+	}
+}
+
+func TestToreDiagonalWrap(t *testing.T) {
+	st := newTore(false)
+	for _, rc := range [][2]int{{2, 1}, {3, 0}, {4, 6}, {5, 5}} {
+		st.Board[rc[0]][rc[1]] = "Y"
+	}
+	if line := WrapLineThrough(st, 3, 0); len(line) != 4 {
+		t.Fatalf("diagonale par le bord : %v", line)
+	}
+}
+
+func TestToreFullRowCountsOnce(t *testing.T) {
+	st := newTore(false)
+	for c := 0; c < st.Cols; c++ {
+		st.Board[5][c] = "R"
+	}
+	st.WinLength = 8
+	if line := WrapLineThrough(st, 5, 3); line != nil {
+		t.Fatalf("une rangée de sept pions ne fait pas huit : %v", line)
+	}
+	st.WinLength = 7
+	if line := WrapLineThrough(st, 5, 3); len(line) != 7 {
+		t.Fatalf("rangée pleine : %v", line)
+	}
+}
+
+func TestBestMoveWraps(t *testing.T) {
+	st := newTore(false)
+	for _, c := range []int{5, 6, 0} {
+		st.Board[5][c] = "R"
+		st.Board[4][c] = "Y"
+	}
+	st.Board[5][4] = "Y" // Seule la colonne 1 gagne
+	if got := BestMove(st, 200*time.Millisecond); got != 1 {
+		t.Fatalf("BestMove = %d, attendu 1 (alignement par le bord)", got)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------- JOURNALISATION STRUCTURÉE ----------------
+// Niveau (P4_LOG_LEVEL = debug|info|warn|error) et format (P4_LOG_FORMAT = text|json)
+// configurables. Chaque ligne porte les attributs utiles à la corrélation : request_id,
+// party, seat, mode, version.
+
+var logger = newLogger(os.Stderr, envOr("P4_LOG_LEVEL", "info"), envOr("P4_LOG_FORMAT", "text"))
+
+// secretKeys : attributs dont la valeur ne doit jamais apparaître dans les journaux
+var secretKeys = map[string]bool{
+	"token":         true,
+	"contact":       true,
+	"password":      true,
+	"authorization": true,
+}
+
+const redacted = "[REDACTED]"
+
+func parseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
+
+func newLogger(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level: parseLevel(level),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if secretKeys[strings.ToLower(a.Key)] {
+				return slog.String(a.Key, redacted)
+			}
+			return a
+		},
+	}
+	var h slog.Handler = slog.NewTextHandler(w, opts)
+	if strings.ToLower(format) == "json" {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(ringHandler{Handler: h, ring: recentErrors})
+}
+
+// recentRecord : ligne de journal conservée pour la page d'administration
+type recentRecord struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Attrs   map[string]interface{} `json:"attrs"`
+}
+
+// errorRing garde les dernières lignes de niveau warn ou plus
+type errorRing struct {
+	mu      sync.Mutex
+	records []recentRecord
+	size    int
+}
+
+var recentErrors = &errorRing{size: 100}
+
+func (e *errorRing) add(rec recentRecord) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, rec)
+	if len(e.records) > e.size {
+		e.records = e.records[len(e.records)-e.size:]
+	}
+}
+
+// list retourne les lignes conservées, la plus récente en premier
+func (e *errorRing) list() []recentRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]recentRecord, len(e.records))
+	for i, rec := range e.records {
+		out[len(out)-1-i] = rec
+	}
+	return out
+}
+
+// ringHandler recopie les avertissements et erreurs dans un errorRing
+type ringHandler struct {
+	slog.Handler
+	ring  *errorRing
+	attrs []slog.Attr
+}
+
+func (h ringHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		rec := recentRecord{Time: r.Time, Level: r.Level.String(), Message: r.Message, Attrs: map[string]interface{}{}}
+		add := func(a slog.Attr) bool {
+			if secretKeys[strings.ToLower(a.Key)] {
+				rec.Attrs[a.Key] = redacted
+			} else {
+				rec.Attrs[a.Key] = a.Value.String()
+			}
+			return true
+		}
+		for _, a := range h.attrs {
+			add(a)
+		}
+		r.Attrs(add)
+		h.ring.add(rec)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	all := append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return ringHandler{Handler: h.Handler.WithAttrs(attrs), ring: h.ring, attrs: all}
+}
+
+func (h ringHandler) WithGroup(name string) slog.Handler {
+	return ringHandler{Handler: h.Handler.WithGroup(name), ring: h.ring, attrs: h.attrs}
+}
+
+// partyAttrs : attributs de corrélation d'une partie. p.Mu doit être verrouillé.
+func (p *Party) partyAttrs() []any {
+	return []any{"party", p.Code, "mode", p.State.Mode, "version", p.State.Version}
+}
+
+// logger retourne le journal de la partie. p.Mu doit être verrouillé.
+func (p *Party) logger() *slog.Logger {
+	return logger.With(p.partyAttrs()...)
+}
+
+// loggerFor retourne le journal de la partie pour une requête HTTP. p.Mu doit être verrouillé.
+func (p *Party) loggerFor(r *http.Request) *slog.Logger {
+	return requestLogger(r).With(p.partyAttrs()...)
+}
+
+type loggerKey struct{}
+
+// requestLogger retourne le journal de la requête (avec son request_id)
+func requestLogger(r *http.Request) *slog.Logger {
+	if l, ok := r.Context().Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return logger
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder retient le code de réponse ; Hijack reste disponible pour les WebSockets
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack non supporté")
+	}
+	s.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
+
+// withRequestLogging attribue un request_id à chaque requête (repris de X-Request-Id s'il
+// est fourni) et journalise la réponse. La query string n'est jamais journalisée : elle
+// peut contenir un jeton de siège.
+func withRequestLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		l := logger.With("request_id", id)
+		w.Header().Set("X-Request-Id", id)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), loggerKey{}, l)))
+
+		level := slog.LevelInfo
+		if strings.HasPrefix(r.URL.Path, "/static/") || strings.HasPrefix(r.URL.Path, "/images/") || r.URL.Path == "/metrics" {
+			level = slog.LevelDebug
+		}
+		l.Log(r.Context(), level, "requête", "method", r.Method, "path", r.URL.Path,
+			"status", rec.status, "duration", time.Since(start))
+	})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLogger(&buf, "debug", "json")
+	l.Info("siège pris", "party", "ABC123", "seat", "R", "token", "s3cr3t", "contact", "a@b.c")
+
+	if strings.Contains(buf.String(), "s3cr3t") || strings.Contains(buf.String(), "a@b.c") {
+		t.Fatalf("secret journalisé : %s", buf.String())
+	}
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("sortie JSON invalide : %v", err)
+	}
+	if line["token"] != redacted || line["party"] != "ABC123" || line["seat"] != "R" {
+		t.Fatalf("attributs inattendus : %v", line)
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := newLogger(&buf, "warn", "text")
+	l.Info("ignoré")
+	l.Warn("gardé")
+	if strings.Contains(buf.String(), "ignoré") || !strings.Contains(buf.String(), "gardé") {
+		t.Fatalf("niveau non respecté : %s", buf.String())
+	}
+}
@@ -1,16 +1,18 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
-	"io"
-	"log"
+	"log/slog"
 	mrand "math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"power4/game"
+	"power4/web"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,7 +50,7 @@ var (
 
 // Templates
 var (
-	indexTmpl = template.Must(template.New("index.html").Funcs(template.FuncMap{
+	templateFuncs = template.FuncMap{
 		"seq": func(a, b int) []int {
 			s := make([]int, b-a+1)
 			for i := a; i <= b; i++ {
@@ -62,27 +64,205 @@ var (
 		"sub": func(a, b int) int {
 			return a - b
 		},
-	}).ParseFiles("templates/index.html"))
-	menuTmpl        = template.Must(template.New("menu.html").ParseFiles("templates/menu.html"))
-	welcomeTmpl     = template.Must(template.ParseFiles("templates/welcome.html"))
-	playersTmpl     = template.Must(template.ParseFiles("templates/players.html"))
-	multiplayerTmpl = template.Must(template.ParseFiles("templates/multiplayer.html"))
-	wsUpgrader      = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
-	wsClients       = map[*websocket.Conn]bool{}
-	wsMu            sync.Mutex
+	}
+	// Pages embarquées ; remplacées au démarrage si cfg.AssetsDir est fourni
+	pages      = web.MustTemplates(web.FS, templateFuncs, false)
+	wsUpgrader = websocket.Upgrader{CheckOrigin: originAllowed}
+	wsClients  = map[*websocket.Conn]bool{}
+	wsMu       sync.Mutex
 )
 
 // ---------------- PARTIES AVEC CODES UNIQUES ----------------
 
 type Party struct {
-	Code           string
-	CreatedAt      time.Time
-	State          game.GameState
-	Clients        map[*websocket.Conn]bool
-	ClientTeam     map[*websocket.Conn]string // Stocke l'équipe de chaque client ('R' ou 'Y')
-	DoublePlayNext bool                       // Pour le booster "double-shot"
-	BlockedColumn  int                        // Colonne bloquée par le booster "block-column"
-	Mu             sync.Mutex
+	Code           string                              `json:"code"`
+	CreatedAt      time.Time                           `json:"createdAt"`
+	UpdatedAt      time.Time                           `json:"updatedAt"`
+	State          game.GameState                      `json:"state"`
+	Clients        map[*websocket.Conn]bool            `json:"-"`
+	ClientTeam     map[*websocket.Conn]string          `json:"-"`                    // Stocke l'équipe de chaque client ('R' ou 'Y')
+	Muted          map[*websocket.Conn]map[string]bool `json:"-"`                    // Équipes dont chaque client masque le chat
+	ClientInfo     map[*websocket.Conn]clientInfo      `json:"-"`                    // Identifiant et adresse de chaque client (administration)
+	DoublePlayNext bool                                `json:"doublePlayNext"`       // Pour le booster "double-shot"
+	BlockedColumn  int                                 `json:"blockedColumn"`        // Colonne bloquée par le booster "block-column"
+	Boosters       map[string][]string                 `json:"boosters,omitempty"`   // Couleur -> boosters ramassés, pas encore utilisés
+	Solo           bool                                `json:"solo"`                 // Les deux joueurs partagent le même écran
+	Async          bool                                `json:"async"`                // Partie par correspondance (persistée sur disque)
+	Seats          map[string]string                   `json:"seats"`                // Équipe -> nom du joueur assis
+	Contacts       map[string]string                   `json:"contacts"`             // Équipe -> contact pour les notifications
+	Tokens         map[string]string                   `json:"tokens"`               // Jeton de siège -> équipe
+	Log            []LogEntry                          `json:"log"`                  // Historique des coups
+	Match          *Match                              `json:"match"`                // Série de manches jouées dans cette partie
+	Tournament     string                              `json:"tournament,omitempty"` // Tournoi auquel appartient la partie
+	PairingID      int                                 `json:"pairingId,omitempty"`  // Rencontre du tournoi jouée ici
+	Reported       bool                                `json:"reported,omitempty"`   // Résultat déjà transmis au tournoi
+	Campaign       *Campaign                           `json:"campaign,omitempty"`   // Progression du mode exponentiel
+	NotifiedMoves  int                                 `json:"notifiedMoves"`        // Nombre de coups au moment de la dernière notification
+	Bots           map[string]string                   `json:"bots,omitempty"`       // Équipe -> moteur qui joue à sa place
+	Teams          *Teams                              `json:"teams,omitempty"`      // Mode 2 contre 2 (nil en 1 contre 1)
+	botThinking    bool                                // Un moteur cherche son coup
+	botProcs       map[string]*botProcess              // Équipe -> processus du moteur assis
+	Round          *round                              `json:"-"` // Tour en cours d'une variante à coups simultanés (blitz)
+	gameStart      time.Time                           // Début de la manche en cours (métriques)
+	finishSeen     bool                                // Fin de la manche en cours déjà mesurée
+	Mu             sync.Mutex                          `json:"-"`
+}
+
+// LogEntry : une entrée de l'historique d'une partie
+type LogEntry struct {
+	At    time.Time `json:"at"`
+	Kind  string    `json:"kind"` // "move", "pop", "swap"..., "booster", "chat", "reaction", "rematch"
+	Team  string    `json:"team"`
+	Row   int       `json:"row"`
+	Col   int       `json:"col"`
+	Text  string    `json:"text,omitempty"`
+	Scope string    `json:"scope,omitempty"` // "team" : message réservé à une équipe (2 contre 2)
+}
+
+// Erreurs renvoyées quand un coup est refusé
+var (
+	errNotYourTurn   = errors.New("Ce n'est pas votre tour!")
+	errColumnBlocked = errors.New("Cette colonne est bloquée!")
+	errIllegalMove   = game.ErrIllegalMove
+)
+
+// newParty prépare une partie vide avec ses maps initialisées
+func newParty(code string, state game.GameState) *Party {
+	now := time.Now()
+	return &Party{
+		Code:          code,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		State:         state,
+		Clients:       make(map[*websocket.Conn]bool),
+		ClientTeam:    make(map[*websocket.Conn]string),
+		Muted:         make(map[*websocket.Conn]map[string]bool),
+		ClientInfo:    make(map[*websocket.Conn]clientInfo),
+		BlockedColumn: -1,
+		Seats:         make(map[string]string),
+		Contacts:      make(map[string]string),
+		Tokens:        make(map[string]string),
+		Match:         newMatch(1),
+		gameStart:     now,
+	}
+}
+
+// broadcast envoie un message à tous les clients connectés. p.Mu doit être verrouillé.
+func (p *Party) broadcast(msg interface{}) {
+	start := time.Now()
+	for c := range p.Clients {
+		_ = c.WriteJSON(msg)
+	}
+	metricBroadcast.observe(time.Since(start))
+}
+
+// broadcastState envoie un message d'état à tous les clients ; chacun reçoit sous "state" la
+// partie vue de son siège (voir stateFor). p.Mu doit être verrouillé.
+func (p *Party) broadcastState(msg map[string]interface{}) {
+	start := time.Now()
+	for c := range p.Clients {
+		_ = p.writeState(c, msg)
+	}
+	metricBroadcast.observe(time.Since(start))
+}
+
+// writeState envoie msg à c avec sous "state" la partie vue de son siège. p.Mu doit être verrouillé.
+func (p *Party) writeState(c *websocket.Conn, msg map[string]interface{}) error {
+	msg["state"] = p.stateFor(p.ClientTeam[c])
+	return c.WriteJSON(msg)
+}
+
+// stateFor retourne la partie envoyée au siège seat : une variante à information cachée
+// (brouillard) masque une partie du plateau jusqu'à la fin de la manche. La table des
+// positions déjà rencontrées (PopOut) reste côté serveur : elle grossit à chaque coup et ne
+// sert pas à l'affichage. p.Mu doit être verrouillé.
+func (p *Party) stateFor(seat string) game.GameState {
+	st := game.View(p.State, seatColor(seat))
+	st.Seen = nil
+	return st
+}
+
+// isConcealed indique si la variante de la partie cache une partie du plateau (game.Concealed)
+func (p *Party) isConcealed() bool {
+	_, ok := game.VariantOf(p.State).(game.Concealed)
+	return ok
+}
+
+// seatsProtected indique si un siège ne s'obtient qu'avec son jeton : partie par correspondance,
+// de tournoi, ou à information cachée (la vue d'un siège ne doit pas se lire d'un simple
+// ?team=). p.Mu doit être verrouillé.
+func (p *Party) seatsProtected() bool {
+	return p.Async || p.Tournament != "" || p.isConcealed()
+}
+
+// requestSeat retourne le siège demandé par r : le paramètre team, ou pour des sièges protégés
+// celui du jeton de siège (paramètre ou cookie, voir requestToken), spectateur sans jeton valide. p.Mu doit être verrouillé.
+func (p *Party) requestSeat(r *http.Request) string {
+	if !p.seatsProtected() {
+		return r.URL.Query().Get("team")
+	}
+	if team, ok := p.Tokens[requestToken(r, p.Code)]; ok {
+		return team
+	}
+	return "S"
+}
+
+// trackGameDuration mesure la durée de chaque manche à sa fin. p.Mu doit être verrouillé.
+func (p *Party) trackGameDuration() {
+	switch {
+	case p.State.Finished && !p.finishSeen:
+		p.finishSeen = true
+		if !p.gameStart.IsZero() { // Inconnue pour une partie rechargée depuis le disque
+			metricGameDuration.observe(time.Since(p.gameStart))
+		}
+	case !p.State.Finished && p.finishSeen:
+		p.finishSeen = false // Nouvelle manche (revanche, niveau suivant)
+		p.gameStart = time.Now()
+	}
+}
+
+// changed est appelé après chaque modification de la partie. p.Mu doit être verrouillé.
+// Les parties par correspondance sont sauvegardées et le joueur dont c'est le tour est notifié.
+func (p *Party) changed() {
+	p.UpdatedAt = time.Now()
+	p.trackGameDuration()
+	p.Match.record(p.State)
+	p.Teams.record(p.State)
+	p.Campaign.record(p.State)
+	p.scheduleBot()
+	if p.Tournament != "" && p.State.Finished && !p.Reported {
+		p.Reported = true
+		go reportTournamentResult(p.Tournament, p.PairingID, p.Seats[p.State.Winner])
+	}
+	if !p.Async {
+		return
+	}
+	if err := saveParty(p); err != nil {
+		p.logger().Error("sauvegarde de la partie impossible", "err", err)
+	}
+	moves := p.moveCount()
+	if p.State.Finished || moves <= p.NotifiedMoves {
+		return
+	}
+	seat := p.turnSeat()
+	player := p.Seats[seat]
+	if player == "" {
+		return
+	}
+	p.NotifiedMoves = moves
+	n := turnNotice{
+		Code:    p.Code,
+		Mode:    p.State.Mode,
+		Team:    seat,
+		Player:  player,
+		Contact: p.Contacts[seat],
+	}
+	l := p.logger().With("seat", n.Team)
+	go func() {
+		if err := notifier.NotifyTurn(n); err != nil {
+			l.Error("notification de tour impossible", "err", err)
+		}
+	}()
 }
 
 var (
@@ -92,7 +272,7 @@ var (
 
 func generateCode() string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, 6)
+	b := make([]byte, cfg.CodeLength)
 	for i := range b {
 		b[i] = charset[mrand.Intn(len(charset))]
 	}
@@ -100,273 +280,523 @@ func generateCode() string {
 }
 
 func createPartyHandler(w http.ResponseWriter, r *http.Request) {
+	if refuseWhileDraining(w) {
+		return
+	}
+	if err := limits.create.check(clientIP(r), ""); err != nil {
+		writeRateLimited(w, r, err)
+		return
+	}
 	partiesMu.Lock()
 	defer partiesMu.Unlock()
 
-	// Récupérer le mode depuis l'URL
-	mode := r.URL.Query().Get("mode")
-	if mode == "" {
-		mode = "solo-classique" // Mode par défaut
+	// Variante et solo/multi choisis séparément (variant, solo), ou ensemble par le mode (multi-turbo...)
+	q := r.URL.Query()
+	mode := q.Get("mode")
+	if mode == "" && q.Get("variant") == "" {
+		mode = cfg.DefaultMode
+	}
+	solo, variant := parseMode(mode)
+	if v := q.Get("variant"); v != "" {
+		variant, solo = v, isTruthy(q.Get("solo"))
+	}
+	v, ok := game.Lookup(variant)
+	if !ok {
+		writeJSONError(w, http.StatusBadRequest, "Variante inconnue")
+		return
 	}
 
-	// Taille de grille facultative (utile pour mode exponentiel)
-	rowsParam := strings.TrimSpace(r.URL.Query().Get("rows"))
-	colsParam := strings.TrimSpace(r.URL.Query().Get("cols"))
-	rows := 6
-	cols := 7
-	if rowsParam != "" {
-		if v, err := strconv.Atoi(rowsParam); err == nil {
-			rows = v
+	// Partie à 3 ou 4 joueurs (chacun pour soi) : une couleur de plus par joueur
+	players := 2
+	if q.Has("players") {
+		n, err := strconv.Atoi(strings.TrimSpace(q.Get("players")))
+		if err != nil || n < 2 || n > len(game.PlayerTokens) {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Nombre de joueurs invalide (entre 2 et %d)", len(game.PlayerTokens)))
+			return
 		}
+		players = n
 	}
-	if colsParam != "" {
-		if v, err := strconv.Atoi(colsParam); err == nil {
-			cols = v
-		}
+	if l, ok := v.(game.PlayerLimited); ok && players > l.MaxPlayers() {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Cette variante se joue à %d joueurs au plus", l.MaxPlayers()))
+		return
 	}
-	// Bornes de sécurité (le tableau est 15x15 max dans GameState)
-	if rows < 4 {
-		rows = 4
+	_, hidden := v.(game.Concealed)
+	_, simultaneous := v.(game.Simultaneous)
+	if (hidden || simultaneous) && solo {
+		writeJSONError(w, http.StatusBadRequest, "Cette variante se joue à plusieurs, chacun sur son écran")
+		return
 	}
-	if cols < 4 {
-		cols = 4
+	if simultaneous && (isTruthy(q.Get("teams")) || isTruthy(q.Get("async"))) {
+		writeJSONError(w, http.StatusBadRequest, "Les coups simultanés se jouent en direct, à deux")
+		return
 	}
-	if rows > 15 {
-		rows = 15
+	if players > 2 && (isTruthy(q.Get("teams")) || q.Get("opt.opening") != "") {
+		writeJSONError(w, http.StatusBadRequest, "Les parties à plus de deux joueurs se jouent chacun pour soi, sans ouverture")
+		return
 	}
-	if cols > 15 {
-		cols = 15
+
+	// Modèle de plateau facultatif : nom d'un modèle par défaut ou modèle au format JSON
+	var tmpl *game.Template
+	if name := strings.TrimSpace(q.Get("template")); name != "" {
+		var err error
+		if tmpl, err = parseTemplateParam(name); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if variant == "popout" || variant == "exponentiel" || q.Get("opt.opening") != "" {
+			writeJSONError(w, http.StatusBadRequest, "Cette variante ne se joue pas sur un modèle de plateau")
+			return
+		}
+		b := cfg.Board
+		if tr, tc := tmpl.Size(); tr < b.MinRows || tr > b.MaxRows || tc < b.MinCols || tc > b.MaxCols {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Modèle de plateau hors des bornes (%dx%d à %dx%d)", b.MinRows, b.MinCols, b.MaxRows, b.MaxCols))
+			return
+		}
+		for _, disc := range tmpl.Discs() {
+			if !contains(game.PlayerTokens[:players], disc) {
+				writeJSONError(w, http.StatusBadRequest, "Le modèle pose des pions d'un joueur absent de la partie")
+				return
+			}
+		}
 	}
 
-	code := generateCode()
-	newState := game.GameState{
-		Rows: rows, Cols: cols, WinLength: 4,
-		Next: "R", Mode: mode,
+	// Taille de grille facultative (utile pour mode exponentiel), ramenée dans les bornes configurées
+	rows, _ := strconv.Atoi(strings.TrimSpace(q.Get("rows")))
+	cols, _ := strconv.Atoi(strings.TrimSpace(q.Get("cols")))
+	if tmpl != nil {
+		rows, cols = tmpl.Size()
+	}
+	if players > 2 && rows == 0 && cols == 0 {
+		// Plateau agrandi par défaut : une ligne et deux colonnes de plus par joueur supplémentaire
+		rows, cols = cfg.Board.DefaultRows+players-2, cfg.Board.DefaultCols+2*(players-2)
 	}
+	if s, ok := v.(game.Sized); ok {
+		defRows, defCols, _ := s.DefaultSize()
+		if rows == 0 {
+			rows = defRows
+		}
+		if cols == 0 {
+			cols = defCols
+		}
+	}
+	rows, cols = cfg.Board.clamp(rows, cols)
 
-	p := &Party{
-		Code:          code,
-		CreatedAt:     time.Now(),
-		State:         newState,
-		Clients:       make(map[*websocket.Conn]bool),
-		ClientTeam:    make(map[*websocket.Conn]string),
-		BlockedColumn: -1,
+	// Longueur d'alignement facultative : il faut pouvoir aligner les pions sur le plateau
+	win := 0
+	if tmpl != nil {
+		win = tmpl.WinLength
+	}
+	if q.Has("win") {
+		n, err := strconv.Atoi(strings.TrimSpace(q.Get("win")))
+		if err != nil || n < 2 || n > max(rows, cols) {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("Longueur d'alignement invalide (entre 2 et %d)", max(rows, cols)))
+			return
+		}
+		win = n
 	}
 
-	// Générer les cases boosters si mode turbo
-	if strings.Contains(mode, "turbo") {
-		generateBoosterCells(&p.State)
+	// Mode 2 contre 2 : quatre sièges, ordre de jeu facultatif (order=R1,Y1,R2,Y2)
+	var order []string
+	if isTruthy(q.Get("teams")) {
+		if solo {
+			writeJSONError(w, http.StatusBadRequest, "Le mode 2 contre 2 se joue en multijoueur")
+			return
+		}
+		var err error
+		if order, err = parseTeamOrder(q.Get("order")); err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	p := newVariantParty(solo, variant, rows, cols, win, q)
+	if order != nil {
+		p.Teams = newTeams(order, p.State.Next)
+	}
+	if players > 2 {
+		p.State.Players = game.PlayerTokens[:players]
+	}
+	// Règle d'élimination : le joueur qui aligne ses pions est classé, les autres continuent
+	p.State.Elimination = q.Get("rule") == "elimination"
+	if tmpl != nil {
+		p.State.Template = tmpl
+		p.initBoard()
+		if game.VariantOf(p.State).Terminal(p.State) {
+			writeJSONError(w, http.StatusBadRequest, "Le modèle de plateau contient déjà un alignement")
+			return
+		}
+	}
+	mode = p.State.Mode
+	code := p.Code
+
+	// Partie par correspondance : le créateur prend directement un siège
+	resp := map[string]string{"code": code}
+	if isTruthy(r.URL.Query().Get("async")) {
+		p.Async = true
+		team := strings.ToUpper(r.URL.Query().Get("team"))
+		if !contains(p.seatList(), team) {
+			team = p.seatList()[0]
+		}
+		resp["team"] = team
+		resp["token"] = p.takeSeat(team, r.URL.Query().Get("player"), r.URL.Query().Get("contact"))
+		p.changed()
 	}
 
 	parties[code] = p
+	metricPartiesCreated.inc(mode)
 
-	log.Printf("✅ Nouvelle partie créée : %s (mode: %s)", code, mode)
+	p.loggerFor(r).Info("partie créée", "async", p.Async)
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"code": code})
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// generateBoosterCells génère aléatoirement des cases contenant des boosters
-func generateBoosterCells(state *game.GameState) {
-	// Utiliser un générateur aléatoire local (Go 1.20+)
-	rng := mrand.New(mrand.NewSource(time.Now().UnixNano()))
+// parseMode découpe un mode "solo-turbo" / "multi-classique" en solo/multi et variante.
+// Un mode sans variante connue donne la variante classique.
+func parseMode(mode string) (solo bool, variant string) {
+	prefix, name, _ := strings.Cut(mode, "-")
+	if _, ok := game.Lookup(name); !ok {
+		name = "classique"
+	}
+	return prefix == "solo", name
+}
 
-	// Liste des types de boosters disponibles
-	boosterTypes := []string{
-		"double-shot",
-		"remove-piece",
-		"block-column",
-		"swap-colors",
-		"wildcard",
+// modeName est l'inverse de parseMode
+func modeName(solo bool, variant string) string {
+	if solo {
+		return "solo-" + variant
 	}
+	return "multi-" + variant
+}
 
-	// Nombre de boosters à placer (1-2 de chaque type)
-	numBoostersPerType := 2
+// newModeParty crée une partie pour un mode "solo-…" ou "multi-…"
+func newModeParty(mode string, rows, cols int, opts url.Values) *Party {
+	solo, variant := parseMode(mode)
+	return newVariantParty(solo, variant, rows, cols, 0, opts)
+}
 
-	// Créer une liste de toutes les positions possibles
-	type Position struct {
-		row, col int
+// parseTemplateParam lit le paramètre template : un modèle au format JSON ou le nom d'un modèle par défaut
+func parseTemplateParam(s string) (*game.Template, error) {
+	if strings.HasPrefix(s, "{") {
+		return game.ParseTemplate([]byte(s))
 	}
-	var positions []Position
-	for r := 0; r < state.Rows; r++ {
-		for c := 0; c < state.Cols; c++ {
-			positions = append(positions, Position{r, c})
-		}
+	if t, ok := game.LookupTemplate(s); ok {
+		return t, nil
 	}
+	return nil, errors.New("Modèle de plateau inconnu")
+}
 
-	// Mélanger les positions
-	rng.Shuffle(len(positions), func(i, j int) {
-		positions[i], positions[j] = positions[j], positions[i]
-	})
-
-	// Placer les boosters
-	posIndex := 0
-	for _, boosterType := range boosterTypes {
-		for i := 0; i < numBoostersPerType && posIndex < len(positions); i++ {
-			pos := positions[posIndex]
-			state.BoosterCells[pos.row][pos.col] = boosterType
-			posIndex++
-			log.Printf("[Boosters] Case booster placée en (%d,%d): %s", pos.row, pos.col, boosterType)
+// newVariantParty crée une partie pour une variante enregistrée dans package game.
+// winLength 0 donne l'alignement par défaut de la variante ou de la configuration.
+// Les options facultatives (bestof, curve, rowstep...) sont lues dans opts ; les options
+// de la variante sont les paramètres préfixés par "opt." (ex : opt.exact=1).
+func newVariantParty(solo bool, variant string, rows, cols, winLength int, opts url.Values) *Party {
+	if winLength == 0 {
+		winLength = cfg.Board.WinLength
+		if s, ok := game.VariantOf(game.GameState{Variant: variant}).(game.Sized); ok {
+			_, _, winLength = s.DefaultSize()
+		}
+	}
+	newState := game.GameState{
+		Rows: rows, Cols: cols, WinLength: winLength,
+		Next: "R", Mode: modeName(solo, variant), Variant: variant,
+		Options: game.Options{"boosters": game.FormatBoosterCounts(cfg.Boosters)},
+	}
+	for key, values := range opts {
+		if name, ok := strings.CutPrefix(key, "opt."); ok && name != "" {
+			newState.Options[name] = values[0]
 		}
 	}
+
+	p := newParty(generateCode(), newState)
+	p.Solo = solo
+	if bestOf, err := strconv.Atoi(opts.Get("bestof")); err == nil {
+		p.Match = newMatch(bestOf)
+	}
+	// Le mode exponentiel est piloté par le serveur : niveaux, taille et score cumulé
+	if variant == "exponentiel" {
+		p.Campaign = newCampaignFromQuery(opts)
+	}
+	p.initBoard()
+	return p
+}
+
+// initBoard prépare le plateau d'une nouvelle manche selon la variante. p.Mu doit être verrouillé.
+func (p *Party) initBoard() {
+	rng := mrand.New(mrand.NewSource(time.Now().UnixNano()))
+	game.Setup(&p.State, rng)
 }
 
 func joinPartyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := limits.join.check(clientIP(r), ""); err != nil {
+		writeRateLimited(w, r, err)
+		return
+	}
 	code := strings.ToUpper(r.URL.Query().Get("code"))
 	partiesMu.Lock()
-	_, exists := parties[code]
+	p, exists := parties[code]
 	partiesMu.Unlock()
 	if !exists {
+		metricPartyFailures.inc("join_not_found")
+		requestLogger(r).Warn("partie introuvable", "party", code)
 		http.Error(w, "Party not found", http.StatusNotFound)
 		return
 	}
+	resp := map[string]string{"status": "joined", "code": code}
+
+	// Partie par correspondance ou à information cachée : attribuer le siège libre au joueur,
+	// avec le jeton qui lui permettra de jouer et de voir sa vue du plateau
+	p.Mu.Lock()
+	if p.Async || (p.isConcealed() && p.Tournament == "") {
+		team := strings.ToUpper(r.URL.Query().Get("team"))
+		if team == "" {
+			team = p.freeSeat()
+		}
+		if !contains(p.seatList(), team) || p.Seats[team] != "" {
+			p.Mu.Unlock()
+			metricPartyFailures.inc("seat_taken")
+			http.Error(w, "Seat not available", http.StatusConflict)
+			return
+		}
+		resp["team"] = team
+		resp["token"] = p.takeSeat(team, r.URL.Query().Get("player"), r.URL.Query().Get("contact"))
+		p.changed()
+	}
+	metricPartyJoins.inc(p.State.Mode)
+	p.loggerFor(r).Info("joueur arrivé", "seat", resp["team"])
+	p.Mu.Unlock()
+
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "joined", "code": code})
-	log.Printf("👥 Un joueur a rejoint la partie %s", code)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func wsPartyHandler(w http.ResponseWriter, r *http.Request) {
+	if refuseWhileDraining(w) {
+		return
+	}
+	if !originAllowed(r) {
+		metricPartyFailures.inc("origin_rejected")
+		requestLogger(r).Warn("origine WebSocket refusée", "origin", r.Header.Get("Origin"))
+		writeJSONError(w, http.StatusForbidden, "Origine non autorisée")
+		return
+	}
+	if err := limits.join.check(clientIP(r), ""); err != nil {
+		writeRateLimited(w, r, err)
+		return
+	}
 	code := strings.TrimPrefix(r.URL.Path, "/ws/")
 	partiesMu.Lock()
 	p, exists := parties[code]
 	partiesMu.Unlock()
 	if !exists {
+		metricPartyFailures.inc("ws_not_found")
+		requestLogger(r).Warn("partie introuvable", "party", code)
 		http.Error(w, "Party not found", http.StatusNotFound)
 		return
 	}
 
-	// Récupérer l'équipe depuis l'URL (query param)
-	team := r.URL.Query().Get("team")
+	// Récupérer l'équipe depuis l'URL (query param), ou depuis le jeton de siège
+	p.Mu.Lock()
+	team := p.requestSeat(r)
 	if team == "" {
 		team = "R" // Par défaut équipe rouge
 	}
+	team = p.connectedSeat(strings.ToUpper(team))
+	p.Mu.Unlock()
 
 	conn, err := wsUpgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+		metricPartyFailures.inc("ws_upgrade")
+		requestLogger(r).Warn("upgrade WebSocket impossible", "party", code, "err", err)
 		return
 	}
+	metricWSClients.Add(1)
+	conn.SetReadLimit(wsReadLimit)
+	ip := clientIP(r)
+	session := newRequestID()
 
 	p.Mu.Lock()
 	p.Clients[conn] = true
 	p.ClientTeam[conn] = team // Stocker l'équipe du client
+	p.ClientInfo[conn] = clientInfo{ID: session, Since: time.Now(), Addr: r.RemoteAddr}
+	// Écrire sous le verrou : une diffusion concurrente sur la même connexion ferait paniquer gorilla/websocket
+	_ = conn.WriteJSON(p.stateFor(team))
+	connLog := p.loggerFor(r).With("seat", team)
 	p.Mu.Unlock()
-
-	_ = conn.WriteJSON(p.State)
+	connLog.Info("client WebSocket connecté")
 
 	go func() {
 		defer func() {
 			p.Mu.Lock()
 			delete(p.Clients, conn)
 			delete(p.ClientTeam, conn)
+			delete(p.ClientInfo, conn)
+			delete(p.Muted, conn)
 			p.Mu.Unlock()
+			chatLimits.forget(conn)
 			conn.Close()
+			metricWSClients.Add(-1)
+			connLog.Info("client WebSocket déconnecté")
 		}()
 
 		for {
 			var msg map[string]interface{}
 			if err := conn.ReadJSON(&msg); err != nil {
+				if errors.Is(err, websocket.ErrReadLimit) {
+					connLog.Warn("message WebSocket trop long", "limit", wsReadLimit)
+				}
 				return
 			}
-			if msg["type"] == "play" {
-				col := int(msg["col"].(float64))
-				handlePartyMove(p, conn, col)
+			text, _ := msg["text"].(string)
+			switch msg["type"] {
+			case "play", "pop":
+				col, ok := msg["col"].(float64)
+				if _, hasKind := msg["kind"]; !ok && !hasKind {
+					sendError(p, conn, "Colonne invalide")
+					break
+				}
+				if err := limits.move.check(ip, session); err != nil {
+					sendError(p, conn, err.Error())
+					break
+				}
+				row, _ := msg["row"].(float64) // Variantes sans gravité
+				move := game.Move{Row: int(row), Col: int(col)}
+				if kind, ok := msg["kind"].(string); ok {
+					move.Kind = kind // Coups d'ouverture (swap, extend, keep)
+				}
+				if msg["type"] == "pop" {
+					move.Kind = game.MovePop
+				}
+				handlePartyMove(p, conn, move)
+			case "chat", "reaction":
+				handleChat(p, conn, msg["type"].(string), text, msg["scope"] == "team")
+			case "rematch-offer", "rematch-accept", "rematch-decline":
+				handleRematch(p, conn, msg["type"].(string))
+			case "next-level":
+				handleNextLevel(p, conn)
+			case "mute", "unmute":
+				team, _ := msg["team"].(string)
+				setMute(p, conn, team, msg["type"] == "mute")
 			}
 		}
 	}()
 }
 
-func handlePartyMove(p *Party, conn *websocket.Conn, col int) {
+// sendError envoie un message d'erreur à un seul client
+func sendError(p *Party, conn *websocket.Conn, message string) {
 	p.Mu.Lock()
 	defer p.Mu.Unlock()
+	_ = conn.WriteJSON(map[string]interface{}{"type": "error", "message": message})
+}
 
-	// En mode solo, on autorise les deux joueurs à jouer
-	// En mode multi, on vérifie que c'est bien le tour du joueur
-	playerTeam := p.ClientTeam[conn]
-	isSoloMode := strings.Contains(p.State.Mode, "solo")
+func handlePartyMove(p *Party, conn *websocket.Conn, m game.Move) {
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
 
-	if !isSoloMode && playerTeam != "" && playerTeam != p.State.Next {
-		// En mode multijoueur, vérifier que c'est le bon tour
-		errorMsg := map[string]interface{}{
-			"type":    "error",
-			"message": "Ce n'est pas votre tour!",
+	// Coups simultanés : la colonne est gardée secrète jusqu'à la fin du tour
+	if p.isSimultaneous() {
+		if err := p.pickColumn(p.ClientTeam[conn], m.Col); err != nil && err != errIllegalMove {
+			_ = conn.WriteJSON(map[string]interface{}{"type": "error", "message": err.Error()})
 		}
-		_ = conn.WriteJSON(errorMsg)
 		return
 	}
 
-	// Vérifier si la colonne est bloquée en mode turbo
-	if strings.Contains(p.State.Mode, "turbo") && col == p.BlockedColumn {
-		log.Printf("Colonne %d bloquée, coup impossible", col)
-		p.BlockedColumn = -1 // Débloquer après tentative
-		errorMsg := map[string]interface{}{
-			"type":    "error",
-			"message": "Cette colonne est bloquée!",
+	boosterObtained, err := p.play(p.ClientTeam[conn], m)
+	if err != nil {
+		if err != errIllegalMove {
+			errorMsg := map[string]interface{}{
+				"type":    "error",
+				"message": err.Error(),
+			}
+			_ = conn.WriteJSON(errorMsg)
 		}
-		_ = conn.WriteJSON(errorMsg)
 		return
 	}
+	p.broadcastMove(conn, boosterObtained)
+}
 
-	if p.State.Finished || col < 0 || col >= p.State.Cols {
-		return
-	}
+// playColumn joue un pion pour l'équipe team dans la colonne col et retourne le booster
+// éventuellement récupéré. p.Mu doit être verrouillé.
+func (p *Party) playColumn(playerTeam string, col int) (string, error) {
+	return p.play(playerTeam, game.Move{Col: col})
+}
 
-	placedRow := -1
-	for r := p.State.Rows - 1; r >= 0; r-- {
-		if p.State.Board[r][col] == "" {
-			p.State.Board[r][col] = p.State.Next
-			placedRow = r
-			break
+// play joue le coup m (pion lâché ou retiré en PopOut) pour l'équipe team. p.Mu doit être verrouillé.
+func (p *Party) play(playerTeam string, m game.Move) (string, error) {
+	col := m.Col
+	// En solo, les deux joueurs partagent l'écran ; sinon on vérifie que c'est bien le tour du joueur.
+	// En 2 contre 2, seul le siège au trait joue.
+	switch {
+	case p.Teams != nil && playerTeam != "":
+		if playerTeam != p.Teams.current() {
+			return "", errNotYourTurn
 		}
+	case !p.Solo && playerTeam != "" && playerTeam != p.State.Next:
+		return "", errNotYourTurn
 	}
 
-	if placedRow == -1 {
-		return // Colonne pleine
+	// Colonne bloquée par le booster "block-column"
+	if col == p.BlockedColumn && m.Kind == game.MoveDrop {
+		p.logger().Debug("coup refusé : colonne bloquée", "seat", playerTeam, "col", col)
+		p.BlockedColumn = -1 // Débloquer après tentative
+		return "", errColumnBlocked
 	}
 
-	// Vérifier si le joueur a placé son pion sur une case booster
-	boosterObtained := ""
-	playerWhoGotBooster := ""
-	if strings.Contains(p.State.Mode, "turbo") && p.State.BoosterCells[placedRow][col] != "" {
-		boosterType := p.State.BoosterCells[placedRow][col]
-		log.Printf("[Booster] Joueur %s a récupéré un booster: %s en (%d,%d)", p.State.Next, boosterType, placedRow, col)
-
-		boosterObtained = boosterType
-		playerWhoGotBooster = p.State.Next
-		p.State.BoosterCells[placedRow][col] = "" // Retirer le booster de la grille
-	}
-
-	if winner := game.WinnerWithLength(p.State.Board, p.State.Rows, p.State.Cols, p.State.WinLength); winner != "" {
-		p.State.Winner = winner
-		p.State.Finished = true
-	} else {
-		// Changer de joueur sauf si double coup est actif
-		if strings.Contains(p.State.Mode, "turbo") && p.DoublePlayNext {
-			// Ne pas changer de joueur, désactiver le double coup
-			p.DoublePlayNext = false
-			log.Printf("[Booster] Double coup utilisé, même joueur rejoue")
-		} else {
-			if p.State.Next == "R" {
-				p.State.Next = "Y"
-			} else {
-				p.State.Next = "R"
-			}
+	mover := p.State.Next
+	out, err := game.Play(&p.State, m)
+	if err != nil {
+		return "", err
+	}
+	kind := "move"
+	if m.Kind != game.MoveDrop {
+		kind = m.Kind
+	}
+	p.Log = append(p.Log, LogEntry{At: time.Now(), Kind: kind, Team: mover, Row: out.Row, Col: out.Col})
+	if out.Booster != "" {
+		if p.Boosters == nil {
+			p.Boosters = make(map[string][]string)
 		}
+		p.Boosters[mover] = append(p.Boosters[mover], out.Booster)
+		p.logger().Info("booster récupéré", "seat", mover, "booster", out.Booster, "row", out.Row, "col", out.Col)
+	}
+	// Double coup : le joueur garde la main
+	if !p.State.Finished && p.DoublePlayNext {
+		p.DoublePlayNext = false
+		p.State.Next = mover
+		p.logger().Debug("double coup utilisé", "seat", mover)
 	}
+	if p.Teams != nil {
+		p.Teams.played(out.Booster, p.State)
+	}
+	metricMoves.inc(p.State.Mode)
 	p.State.Version++
+	p.logger().Debug("coup joué", "seat", mover, "row", out.Row, "col", out.Col)
+	if p.State.Finished {
+		p.logger().Info("manche terminée", "winner", p.State.Winner)
+	}
+	p.changed()
+	return out.Booster, nil
+}
 
-	// Envoyer la mise à jour avec le booster éventuel
+// broadcastMove envoie le nouvel état après un coup ; seul l'auteur du coup (from)
+// reçoit le booster récupéré. p.Mu doit être verrouillé.
+func (p *Party) broadcastMove(from *websocket.Conn, boosterObtained string) {
+	start := time.Now()
+	playerWhoGotBooster := ""
+	if boosterObtained != "" && len(p.Log) > 0 {
+		playerWhoGotBooster = p.Log[len(p.Log)-1].Team
+	}
 	for c := range p.Clients {
 		response := map[string]interface{}{
 			"type":    "state",
-			"state":   p.State,
 			"blocked": p.BlockedColumn,
 		}
-		if boosterObtained != "" && c == conn {
+		if boosterObtained != "" && c == from {
 			response["booster"] = boosterObtained
 			response["player"] = playerWhoGotBooster
 		}
-		_ = c.WriteJSON(response)
+		_ = p.writeState(c, response)
 	}
+	metricBroadcast.observe(time.Since(start))
 }
 
 func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
@@ -374,18 +804,13 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-
-	// Lire le body brut pour debug
-	bodyBytes, _ := io.ReadAll(r.Body)
-	bodyString := string(bodyBytes)
-	log.Printf("[Booster] Body brut reçu: %s", bodyString)
-
-	// Recréer le body pour ParseForm
-	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if refuseWhileDraining(w) {
+		return
+	}
 
 	// Parser le formulaire
 	if err := r.ParseForm(); err != nil {
-		log.Printf("[Booster] Erreur ParseForm: %v", err)
+		requestLogger(r).Warn("formulaire booster invalide", "err", err)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": false,
@@ -398,9 +823,6 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 	player := r.FormValue("player")
 	code := r.FormValue("code")
 
-	log.Printf("[Booster] Action reçue - action: '%s', player: '%s', code: '%s'", action, player, code)
-	log.Printf("[Booster] Tous les FormValues: %v", r.Form)
-
 	if code == "" {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -415,6 +837,7 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 	partiesMu.Unlock()
 
 	if !exists {
+		metricPartyFailures.inc("booster_not_found")
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": false,
@@ -425,18 +848,63 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 
 	p.Mu.Lock()
 	defer p.Mu.Unlock()
+	// La session est le siège du jeton s'il est valide, sinon l'adresse du client dans cette
+	// partie : un nom de joueur ou un jeton inventés ne donnent pas de nouveau seau
+	token := requestToken(r, code)
+	session := clientIP(r) + "/" + code
+	if _, ok := p.Tokens[token]; ok {
+		session = token
+	}
+	if err := limits.booster.check(clientIP(r), session); err != nil {
+		writeRateLimited(w, r, err)
+		return
+	}
+	if !contains(game.BoosterTypes, action) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"message": "Action inconnue",
+		})
+		return
+	}
+	if p.State.Variant != "turbo" {
+		writeJSONError(w, http.StatusBadRequest, "Pas de boosters dans cette variante")
+		return
+	}
+	// Sièges protégés (correspondance, tournoi, brouillard) : la couleur vient du jeton de siège
+	if p.seatsProtected() {
+		seat, ok := p.Tokens[token]
+		if !ok {
+			writeJSONError(w, http.StatusForbidden, "Jeton de siège invalide")
+			return
+		}
+		if player == "" {
+			player = seatColor(seat)
+		}
+		if player != seatColor(seat) {
+			writeJSONError(w, http.StatusForbidden, "Ce booster n'est pas le vôtre")
+			return
+		}
+	}
+	if !p.takeBooster(player, action) {
+		writeJSONError(w, http.StatusForbidden, "Booster non récupéré")
+		return
+	}
+	defer p.changed()
+	l := p.loggerFor(r).With("seat", player, "booster", action)
+
+	metricBoosterUses.inc(action)
+	l.Info("booster utilisé")
 
 	switch action {
 	case "double-shot":
 		// Activer le double coup pour le joueur
 		p.DoublePlayNext = true
-		log.Printf("[Booster] Double coup activé pour joueur %s dans partie %s", player, code)
 
 		// Notifier tous les clients
 		for c := range p.Clients {
-			_ = c.WriteJSON(map[string]interface{}{
+			_ = p.writeState(c, map[string]interface{}{
 				"type":    "state",
-				"state":   p.State,
 				"message": "Double coup activé!",
 			})
 		}
@@ -451,25 +919,21 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 		// Retirer un pion
 		rowStr := r.FormValue("row")
 		colStr := r.FormValue("col")
-		log.Printf("[Booster] Retrait de pion à (%s, %s) par joueur %s", rowStr, colStr, player)
 
 		// Convertir et retirer le pion
 		var row, col int
 		fmt.Sscanf(rowStr, "%d", &row)
 		fmt.Sscanf(colStr, "%d", &col)
 
-		if row >= 0 && row < p.State.Rows && col >= 0 && col < p.State.Cols {
+		if row >= 0 && row < p.State.Rows && col >= 0 && col < p.State.Cols && !game.Fixed(p.State.Board[row][col]) {
 			p.State.Board[row][col] = ""
 			p.State.Version++
-			log.Printf("[Booster] Pion retiré à (%d, %d)", row, col)
+			l.Debug("pion retiré", "row", row, "col", col)
 		}
 
 		// Notifier tous les clients
 		for c := range p.Clients {
-			_ = c.WriteJSON(map[string]interface{}{
-				"type":  "state",
-				"state": p.State,
-			})
+			_ = p.writeState(c, map[string]interface{}{"type": "state"})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -481,20 +945,18 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 	case "block-column":
 		// Bloquer une colonne
 		colStr := r.FormValue("col")
-		log.Printf("[Booster] Blocage de la colonne %s par joueur %s", colStr, player)
 
 		var col int
 		fmt.Sscanf(colStr, "%d", &col)
 
 		p.BlockedColumn = col
 		p.State.Version++
-		log.Printf("[Booster] Colonne %d bloquée", col)
+		l.Debug("colonne bloquée", "col", col)
 
 		// Notifier tous les clients
 		for c := range p.Clients {
-			_ = c.WriteJSON(map[string]interface{}{
+			_ = p.writeState(c, map[string]interface{}{
 				"type":    "state",
-				"state":   p.State,
 				"blocked": p.BlockedColumn,
 			})
 		}
@@ -511,7 +973,6 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 		col1Str := r.FormValue("col1")
 		row2Str := r.FormValue("row2")
 		col2Str := r.FormValue("col2")
-		log.Printf("[Booster] Échange de pions (%s,%s) <-> (%s,%s) par joueur %s", row1Str, col1Str, row2Str, col2Str, player)
 
 		var row1, col1, row2, col2 int
 		fmt.Sscanf(row1Str, "%d", &row1)
@@ -521,18 +982,16 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 
 		// Échanger les pions
 		if row1 >= 0 && row1 < p.State.Rows && col1 >= 0 && col1 < p.State.Cols &&
-			row2 >= 0 && row2 < p.State.Rows && col2 >= 0 && col2 < p.State.Cols {
+			row2 >= 0 && row2 < p.State.Rows && col2 >= 0 && col2 < p.State.Cols &&
+			!game.Fixed(p.State.Board[row1][col1]) && !game.Fixed(p.State.Board[row2][col2]) {
 			p.State.Board[row1][col1], p.State.Board[row2][col2] = p.State.Board[row2][col2], p.State.Board[row1][col1]
 			p.State.Version++
-			log.Printf("[Booster] Pions échangés entre (%d,%d) et (%d,%d)", row1, col1, row2, col2)
+			l.Debug("pions échangés", "row1", row1, "col1", col1, "row2", row2, "col2", col2)
 		}
 
 		// Notifier tous les clients
 		for c := range p.Clients {
-			_ = c.WriteJSON(map[string]interface{}{
-				"type":  "state",
-				"state": p.State,
-			})
+			_ = p.writeState(c, map[string]interface{}{"type": "state"})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -545,7 +1004,6 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 		// Placer un pion n'importe où
 		rowStr := r.FormValue("row")
 		colStr := r.FormValue("col")
-		log.Printf("[Booster] Placement joker à (%s, %s) par joueur %s", rowStr, colStr, player)
 
 		var row, col int
 		fmt.Sscanf(rowStr, "%d", &row)
@@ -555,30 +1013,25 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 		if row >= 0 && row < p.State.Rows && col >= 0 && col < p.State.Cols && p.State.Board[row][col] == "" {
 			p.State.Board[row][col] = player
 			p.State.Version++
-			log.Printf("[Booster] Joker placé à (%d,%d) pour joueur %s", row, col, player)
+			l.Debug("joker placé", "row", row, "col", col)
 
-			// Vérifier victoire
-			winner := game.WinnerWithLength(p.State.Board, p.State.Rows, p.State.Cols, p.State.WinLength)
-			if winner != "" {
-				p.State.Winner = winner
+			// Vérifier la fin de manche selon les règles de la variante
+			if v := game.VariantOf(p.State); v.Terminal(p.State) {
+				p.State.Winner = v.Result(p.State)
 				p.State.Finished = true
-				log.Printf("[Booster] Victoire détectée pour joueur %s après joker!", winner)
-			} else {
-				// Changer de joueur
-				if p.State.Next == "R" {
-					p.State.Next = "Y"
-				} else {
-					p.State.Next = "R"
+				if lined, ok := v.(game.Lined); ok && p.State.Winner != "" {
+					p.State.WinLine = lined.WinningLine(p.State)
 				}
+				l.Info("manche terminée après joker", "winner", p.State.Winner)
+			} else {
+				p.State.Next = game.NextPlayer(p.State, p.State.Next)
+				p.syncTeams()
 			}
 		}
 
 		// Notifier tous les clients
 		for c := range p.Clients {
-			_ = c.WriteJSON(map[string]interface{}{
-				"type":  "state",
-				"state": p.State,
-			})
+			_ = p.writeState(c, map[string]interface{}{"type": "state"})
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -587,25 +1040,31 @@ func boosterActionHandler(w http.ResponseWriter, r *http.Request) {
 			"message": "Joker placé",
 		})
 
-	default:
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": false,
-			"message": "Action inconnue",
-		})
 	}
 }
 
+// takeBooster retire le booster action de ceux ramassés par player ; faux s'il n'en a pas.
+// p.Mu doit être verrouillé.
+func (p *Party) takeBooster(player, action string) bool {
+	owned := p.Boosters[player]
+	i := slices.Index(owned, action)
+	if i < 0 {
+		return false
+	}
+	p.Boosters[player] = slices.Delete(owned, i, i+1)
+	return true
+}
+
 // ---------------- HANDLERS CLASSIQUES (inchangés) ----------------
 
 func welcomeHandler(w http.ResponseWriter, r *http.Request) {
-	if err := welcomeTmpl.Execute(w, nil); err != nil {
+	if err := pages.Execute(w, "welcome.html", nil); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func playersHandler(w http.ResponseWriter, r *http.Request) {
-	if err := playersTmpl.Execute(w, nil); err != nil {
+	if err := pages.Execute(w, "players.html", nil); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -624,11 +1083,29 @@ func setPlayersHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func menuHandler(w http.ResponseWriter, r *http.Request) {
-	if err := menuTmpl.Execute(w, nil); err != nil {
+	if err := pages.Execute(w, "menu.html", nil); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
+// playerView : joueur affiché sur la page de jeu
+type playerView struct {
+	Token string
+	Name  string
+	Emoji string
+}
+
+var playerEmoji = map[string]string{"R": "🔴", "Y": "🟡", "G": "🟢", "B": "🔵"}
+
+// playerViews prépare les joueurs tokens pour la page de jeu. p.Mu doit être verrouillé.
+func (p *Party) playerViews(tokens []string) []playerView {
+	var views []playerView
+	for _, t := range tokens {
+		views = append(views, playerView{Token: t, Name: p.seatName(t), Emoji: playerEmoji[t]})
+	}
+	return views
+}
+
 func gameHandler(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
 	if code == "" {
@@ -648,22 +1125,47 @@ func gameHandler(w http.ResponseWriter, r *http.Request) {
 	p.Mu.Lock()
 	defer p.Mu.Unlock()
 
+	// Information cachée (brouillard) : la page montre la partie vue du siège du jeton
 	data := struct {
 		game.GameState
 		Player1Name   string
 		Player2Name   string
 		BlockedColumn int
 		Code          string
+		Chat          []chatLine
+		Reactions     []string
+		Match         *Match
+		Campaign      *Campaign
+		Teams         []seatView
+		PlayerList    []playerView // Joueurs dans l'ordre de jeu
+		Podium        []playerView // Joueurs déjà classés (règle d'élimination)
+		WrapH         bool         // Bords gauche et droit rejoints (variante tore)
+		WrapV         bool         // Bords haut et bas rejoints
+		Picked        []string     // Coups simultanés : joueurs ayant choisi leur colonne
+		RoundEnds     int64        // Fin du tour en cours (ms Unix, 0 sans tour en cours)
+		ChatMaxLength int          // Longueur maximale d'un message (cfg.Chat)
 	}{
-		GameState:     p.State,
+		GameState:     p.stateFor(p.requestSeat(r)),
 		Player1Name:   playerNames[0],
 		Player2Name:   playerNames[1],
 		BlockedColumn: p.BlockedColumn,
 		Code:          code,
+		Chat:          p.chatLines(),
+		Reactions:     chatReactionList,
+		Match:         p.Match,
+		Campaign:      p.Campaign,
+		Teams:         p.teamView(),
+		PlayerList:    p.playerViews(game.PlayersOf(p.State)),
+		Podium:        p.playerViews(p.State.Ranking),
+		ChatMaxLength: cfg.Chat.MaxLength,
+	}
+	data.WrapH, data.WrapV = game.Wraps(p.State)
+	if picked, ends := p.roundPicks(); picked != nil {
+		data.Picked, data.RoundEnds = picked, ends.UnixMilli()
 	}
 
-	if err := indexTmpl.Execute(w, data); err != nil {
-		log.Printf("template execute error: %v", err)
+	if err := pages.Execute(w, "index.html", data); err != nil {
+		p.loggerFor(r).Error("rendu du template impossible", "template", "index.html", "err", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -680,17 +1182,68 @@ func main() {
 	http.HandleFunc("/api/party/join", joinPartyHandler)
 	http.HandleFunc("/ws/", wsPartyHandler)
 	http.HandleFunc("/booster-action", boosterActionHandler)
-
-	// Fichiers statiques
-	fs := http.FileServer(http.Dir("templates"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
-	imgFs := http.FileServer(http.Dir("Images"))
-	http.Handle("/images/", http.StripPrefix("/images/", imgFs))
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	http.HandleFunc("/api/party/move", partyMoveHandler)
+	http.HandleFunc("/api/party/bot", partyBotHandler)
+	http.HandleFunc("/metrics", metricsHandler)
+	http.HandleFunc("/api/config", configHandler)
+	http.HandleFunc("/api/templates", templatesHandler)
+	http.HandleFunc("/editor", editorPageHandler)
+	http.HandleFunc("/admin", adminOnly(adminPageHandler))
+	http.HandleFunc("/api/admin/parties", adminOnly(adminPartiesHandler))
+	http.HandleFunc("/api/admin/party", adminOnly(adminPartyHandler))
+	http.HandleFunc("/api/admin/party/finish", adminOnly(adminFinishHandler))
+	http.HandleFunc("/api/admin/party/delete", adminOnly(adminDeleteHandler))
+	http.HandleFunc("/api/admin/kick", adminOnly(adminKickHandler))
+	http.HandleFunc("/api/admin/announce", adminOnly(adminAnnounceHandler))
+	http.HandleFunc("/api/admin/errors", adminOnly(adminErrorsHandler))
+	http.HandleFunc("/api/my-games", myGamesHandler)
+	http.HandleFunc("/my-games", myGamesPageHandler)
+	http.HandleFunc("/api/tournament/create", tournamentCreateHandler)
+	http.HandleFunc("/api/tournament/register", tournamentRegisterHandler)
+	http.HandleFunc("/api/tournament/start", tournamentStartHandler)
+	http.HandleFunc("/api/tournament/standings", tournamentStandingsHandler)
+	http.HandleFunc("/api/tournament/bracket", tournamentBracketHandler)
+	http.HandleFunc("/api/tournaments", tournamentListHandler)
+	http.HandleFunc("/tournament", tournamentPageHandler)
+
+	// Les bibliothèques qui utilisent encore le package log passent par le même journal
+	slog.SetDefault(logger)
+
+	var err error
+	if cfg, err = loadConfig(os.Args[1:], os.Getenv); err != nil {
+		logger.Error("configuration invalide", "err", err)
+		os.Exit(2)
+	}
+	limits = newRateLimiters(cfg.RateLimits)
+	adminToken = cfg.AdminToken
+	registeredEngines, botMoveTime = engineCommands(cfg.Engines.Commands), cfg.Engines.MoveTime.Duration
+	bannedWords = loadBannedWords(cfg.Chat)
+	notifier = newNotifier(cfg.Notifier)
+
+	// Fichiers statiques : embarqués, ou lus sur disque depuis cfg.AssetsDir en développement
+	assets := web.Assets(cfg.AssetsDir)
+	cached := cfg.AssetsDir == ""
+	if !cached {
+		pages = web.MustTemplates(assets, templateFuncs, true)
+	}
+	http.Handle("/static/", http.StripPrefix("/static/", web.FileServer(assets, "templates", cached)))
+	http.Handle("/images/", http.StripPrefix("/images/", web.FileServer(assets, "Images", cached)))
+	http.Handle("/vendor/", http.StripPrefix("/vendor/", web.FileServer(assets, "particles.js-master", cached)))
+
+	// Recharger les parties par correspondance sauvegardées
+	loadParties()
+	loadTournaments()
+	go expireParties()
+
+	srv := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           withRequestLogging(http.DefaultServeMux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Info("serveur démarré", "addr", cfg.Listen, "tls", cfg.TLS.Cert != "")
+	if err := serve(srv); err != nil {
+		logger.Error("arrêt du serveur", "err", err)
+		os.Exit(1)
 	}
-	log.Printf("✅ Serveur démarré sur : http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe("0.0.0.0:"+port, nil))
+	logger.Info("serveur arrêté")
 }
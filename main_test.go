@@ -0,0 +1,317 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"power4/game"
+	"strings"
+	"testing"
+)
+
+// createParty appelle /api/party/create et retourne la partie créée
+func createParty(t *testing.T, query string) *Party {
+	t.Helper()
+	limits = newRateLimiters(defaultConfig().RateLimits)
+	w := httptest.NewRecorder()
+	createPartyHandler(w, httptest.NewRequest(http.MethodGet, "/api/party/create?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("création %q : code %d (%s)", query, w.Code, w.Body)
+	}
+	var resp map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	partiesMu.Lock()
+	p := parties[resp["code"]]
+	partiesMu.Unlock()
+	t.Cleanup(func() {
+		partiesMu.Lock()
+		delete(parties, p.Code)
+		partiesMu.Unlock()
+	})
+	return p
+}
+
+// expectCreateRejected vérifie que /api/party/create refuse query (400)
+func expectCreateRejected(t *testing.T, query string) {
+	t.Helper()
+	limits = newRateLimiters(defaultConfig().RateLimits)
+	w := httptest.NewRecorder()
+	createPartyHandler(w, httptest.NewRequest(http.MethodGet, "/api/party/create?"+query, nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("%s : code %d, attendu 400", query, w.Code)
+	}
+}
+
+func TestCreatePartyVariantIndependentOfSolo(t *testing.T) {
+	cases := []struct {
+		query, variant, mode string
+		solo                 bool
+	}{
+		{"mode=multi-turbo", "turbo", "multi-turbo", false},
+		{"mode=solo-exponentiel", "exponentiel", "solo-exponentiel", true},
+		{"variant=turbo&solo=1", "turbo", "solo-turbo", true},
+		{"variant=classique", "classique", "multi-classique", false},
+		{"mode=multijoueur", "classique", "multi-classique", false}, // Ancien mode sans variante
+	}
+	for _, c := range cases {
+		p := createParty(t, c.query)
+		if p.State.Variant != c.variant || p.State.Mode != c.mode || p.Solo != c.solo {
+			t.Errorf("%s : variant=%s mode=%s solo=%v", c.query, p.State.Variant, p.State.Mode, p.Solo)
+		}
+	}
+
+	expectCreateRejected(t, "variant=inconnue")
+}
+
+func TestPlayColumnFollowsTurnsOutsideSolo(t *testing.T) {
+	p := createParty(t, "mode=multi-classique")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if _, err := p.playColumn("Y", 3); err != errNotYourTurn {
+		t.Fatalf("coup hors tour : %v", err)
+	}
+	if _, err := p.playColumn("R", 3); err != nil || p.State.Next != "Y" || p.State.Board[5][3] != "R" {
+		t.Fatalf("coup de R : %v, next=%s", err, p.State.Next)
+	}
+}
+
+func TestPopoutPartyPop(t *testing.T) {
+	p := createParty(t, "variant=popout")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if _, err := p.playColumn("R", 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.play("Y", game.Move{Kind: game.MovePop, Col: 2}); err != errIllegalMove {
+		t.Fatalf("retrait d'un pion adverse : %v", err)
+	}
+	if _, err := p.playColumn("Y", 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.play("R", game.Move{Kind: game.MovePop, Col: 2}); err != nil || p.State.Board[5][2] != "" {
+		t.Fatalf("retrait de R : %v", err)
+	}
+	if last := p.Log[len(p.Log)-1]; last.Kind != "pop" || last.Col != 2 {
+		t.Fatalf("historique : %+v", last)
+	}
+}
+
+func TestSeenKeptOutOfClientState(t *testing.T) {
+	p := createParty(t, "variant=popout")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	for i, col := range []int{0, 1, 2, 3} {
+		if _, err := p.playColumn([]string{"R", "Y"}[i%2], col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(p.State.Seen) == 0 {
+		t.Fatal("positions rencontrées non enregistrées")
+	}
+	if st := p.stateFor("R"); st.Seen != nil {
+		t.Fatalf("positions envoyées au client : %d", len(st.Seen))
+	}
+	// La sauvegarde les garde pour la règle de répétition
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Party
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.State.Seen) != len(p.State.Seen) {
+		t.Fatalf("sauvegarde : %d positions, attendu %d", len(saved.State.Seen), len(p.State.Seen))
+	}
+}
+
+func TestCreatePartyWinLength(t *testing.T) {
+	p := createParty(t, "variant=classique&win=5")
+	if p.State.WinLength != 5 {
+		t.Fatalf("win=5 : winLength=%d", p.State.WinLength)
+	}
+	p = createParty(t, "variant=gomoku")
+	if p.State.Rows != 15 || p.State.Cols != 15 || p.State.WinLength != 5 {
+		t.Fatalf("gomoku par défaut : %dx%d, winLength=%d", p.State.Rows, p.State.Cols, p.State.WinLength)
+	}
+	for _, q := range []string{"win=1", "win=8", "win=abc", "win="} {
+		expectCreateRejected(t, "variant=classique&rows=6&cols=7&"+q)
+	}
+}
+
+func TestCreatePartyMorePlayers(t *testing.T) {
+	p := createParty(t, "variant=classique&players=3&rule=elimination")
+	if len(p.State.Players) != 3 || !p.State.Elimination || p.State.Rows != 7 || p.State.Cols != 9 {
+		t.Fatalf("3 joueurs : players=%v elimination=%v %dx%d", p.State.Players, p.State.Elimination, p.State.Rows, p.State.Cols)
+	}
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if _, err := p.playColumn("G", 0); err != errNotYourTurn {
+		t.Fatalf("G a joué avant son tour : %v", err)
+	}
+	for _, team := range []string{"R", "Y", "G"} {
+		if _, err := p.playColumn(team, 0); err != nil {
+			t.Fatalf("%s : %v", team, err)
+		}
+	}
+
+	// La manche suivante est commencée par le joueur qui suit le précédent premier joueur
+	p.State.Finished = true
+	p.startNextGame()
+	if p.State.Next != "Y" || len(p.State.Players) != 3 || p.State.Board[p.State.Rows-1][0] != "" {
+		t.Fatalf("manche suivante : next=%s players=%v", p.State.Next, p.State.Players)
+	}
+
+	for _, q := range []string{"players=5", "players=1", "players=3&teams=1", "variant=gomoku&players=4&opt.opening=swap2", "variant=misere&players=3"} {
+		expectCreateRejected(t, q)
+	}
+}
+
+func TestCreatePartyTemplate(t *testing.T) {
+	p := createParty(t, "variant=classique&template=Croix")
+	if p.State.Rows != 7 || p.State.Cols != 7 || p.State.Board[0][0] != game.Hole || p.State.Template == nil {
+		t.Fatalf("modèle Croix : %dx%d, case (0,0) %q", p.State.Rows, p.State.Cols, p.State.Board[0][0])
+	}
+	p = createParty(t, "variant=turbo&template="+url.QueryEscape(`{"name":"mini","winLength":3,"cells":["#....","#....","#....","#R.Y."]}`))
+	if p.State.WinLength != 3 || p.State.Board[3][1] != "R" || p.State.BoosterCells[0][0] != "" {
+		t.Fatalf("modèle JSON : winLength=%d, rangée du bas %q", p.State.WinLength, p.State.Board[3])
+	}
+
+	// Le modèle est reposé à chaque manche
+	p.Mu.Lock()
+	p.State.Finished = true
+	p.startNextGame()
+	p.Mu.Unlock()
+	if p.State.Board[3][1] != "R" || p.State.Board[3][0] != game.Blocked {
+		t.Fatalf("manche suivante : rangée du bas %q", p.State.Board[3])
+	}
+
+	for _, q := range []string{
+		"template=Inconnu",
+		"variant=popout&template=Croix",
+		"template=" + url.QueryEscape(`{"cells":["...","..."]}`),
+		"template=" + url.QueryEscape(`{"cells":["....","....","....","GGG."]}`),
+		"template=" + url.QueryEscape(`{"cells":["....","....","....","RRRR"]}`),
+		"template=" + url.QueryEscape(`{"winLength":1,"cells":["....","....","....","...."]}`),
+		"template=" + url.QueryEscape(`{"winLength":0,"cells":["....","....","....","...."]}`),
+		"template=" + url.QueryEscape(`{"winLength":5,"cells":["....","....","....","...."]}`),
+		"template=" + url.QueryEscape(`{"winLength":1000000,"cells":["....","....","....","...."]}`),
+	} {
+		expectCreateRejected(t, q)
+	}
+}
+
+func TestFogPartyStatePerSeat(t *testing.T) {
+	p := createParty(t, "variant=brouillard")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	for _, col := range []int{0, 6} {
+		if _, err := p.playColumn(p.State.Next, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := p.stateFor("R").Board[5][6]; got != game.Hidden {
+		t.Fatalf("R voit le pion lointain de Y : %q", got)
+	}
+	if got := p.stateFor("Y").Board[5][6]; got != "Y" {
+		t.Fatalf("Y ne voit pas son pion : %q", got)
+	}
+	if got := p.stateFor("S").Board[5][0]; got != game.Hidden {
+		t.Fatalf("un spectateur voit les pions : %q", got)
+	}
+
+	expectCreateRejected(t, "variant=brouillard&solo=1")
+}
+
+func TestFogViewNeedsSeatToken(t *testing.T) {
+	p := createParty(t, "variant=brouillard")
+	p.Mu.Lock()
+	for _, col := range []int{0, 6} {
+		if _, err := p.playColumn(p.State.Next, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.Mu.Unlock()
+
+	w := httptest.NewRecorder()
+	joinPartyHandler(w, httptest.NewRequest(http.MethodPost, "/api/party/join?code="+p.Code+"&team=Y", nil))
+	var joined map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&joined); err != nil || joined["token"] == "" {
+		t.Fatalf("jeton du siège Y : code %d, %v (%v)", w.Code, joined, err)
+	}
+	// Le siège est pris : un second joueur ne peut pas le réclamer
+	w = httptest.NewRecorder()
+	joinPartyHandler(w, httptest.NewRequest(http.MethodPost, "/api/party/join?code="+p.Code+"&team=Y", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("siège Y réclamé deux fois : code %d", w.Code)
+	}
+
+	// Cases cachées sur la page : les deux pions pour un spectateur, celui de R pour Y
+	fogged := func(query string) int {
+		w := httptest.NewRecorder()
+		gameHandler(w, httptest.NewRequest(http.MethodGet, "/game?code="+p.Code+query, nil))
+		return strings.Count(w.Body.String(), `class="cell fogged"`)
+	}
+	for query, want := range map[string]int{
+		"&team=Y":                          2,
+		"&team=Y&token=faux":               2,
+		"&team=R&token=" + joined["token"]: 1,
+		"&token=" + joined["token"]:        1,
+	} {
+		if got := fogged(query); got != want {
+			t.Errorf("%s : %d cases cachées, attendu %d", query, got, want)
+		}
+	}
+
+	// Depuis le navigateur, le jeton arrive dans le cookie de la partie
+	req := httptest.NewRequest(http.MethodGet, "/game?code="+p.Code, nil)
+	req.AddCookie(&http.Cookie{Name: seatCookie(p.Code), Value: joined["token"]})
+	w = httptest.NewRecorder()
+	gameHandler(w, req)
+	if got := strings.Count(w.Body.String(), `class="cell fogged"`); got != 1 {
+		t.Errorf("jeton en cookie : %d cases cachées, attendu 1", got)
+	}
+
+	// Même règle pour le WebSocket
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if seat := p.requestSeat(httptest.NewRequest(http.MethodGet, "/ws/"+p.Code+"?team=Y", nil)); seat != "S" {
+		t.Errorf("?team=Y sans jeton : siège %q", seat)
+	}
+	if seat := p.requestSeat(httptest.NewRequest(http.MethodGet, "/ws/"+p.Code+"?team=R&token="+joined["token"], nil)); seat != "Y" {
+		t.Errorf("jeton de Y : siège %q", seat)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/ws/"+p.Code, nil)
+	req.AddCookie(&http.Cookie{Name: seatCookie(p.Code), Value: joined["token"]})
+	if seat := p.requestSeat(req); seat != "Y" {
+		t.Errorf("jeton de Y en cookie : siège %q", seat)
+	}
+}
+
+func TestBlitzPicksStaySecretUntilRoundEnds(t *testing.T) {
+	p := createParty(t, "variant=blitz&opt.round=60")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if err := p.pickColumn("R", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.pickColumn("R", 3); err != errAlreadyPicked {
+		t.Fatalf("second choix : %v", err)
+	}
+	if p.State.Board[5][2] != "" || p.Round == nil {
+		t.Fatal("le pion ne tombe qu'à la fin du tour")
+	}
+	if err := p.pickColumn("Y", 4); err != nil {
+		t.Fatal(err)
+	}
+	if p.Round != nil || p.State.Board[5][2] != "R" || p.State.Board[5][4] != "Y" || p.State.Next != "Y" {
+		t.Fatalf("tour résolu : rangée du bas %q, next=%s", p.State.Board[5], p.State.Next)
+	}
+
+	for _, q := range []string{"variant=blitz&solo=1", "variant=blitz&teams=1", "variant=blitz&players=3"} {
+		expectCreateRejected(t, q)
+	}
+}
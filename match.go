@@ -0,0 +1,144 @@
+package main
+
+import (
+	"power4/game"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------------- REVANCHES ET SÉRIES (BEST-OF-N) ----------------
+
+// Match regroupe les manches successives jouées par les mêmes sièges dans une partie.
+// BestOf vaut 1 pour une suite libre de revanches, 3/5/7 pour une série au meilleur de N.
+type Match struct {
+	BestOf       int            `json:"bestOf"`
+	Game         int            `json:"game"`         // Numéro de la manche en cours (à partir de 1)
+	Starter      string         `json:"starter"`      // Équipe qui commence la manche en cours
+	Score        map[string]int `json:"score"`        // Manches gagnées par équipe
+	Draws        int            `json:"draws"`        // Manches nulles
+	Winner       string         `json:"winner"`       // Vainqueur de la série
+	Finished     bool           `json:"finished"`     // Série terminée
+	RematchOffer string         `json:"rematchOffer"` // Équipe qui propose la revanche ("" si aucune)
+	Recorded     bool           `json:"recorded"`     // Résultat de la manche en cours déjà compté
+}
+
+func newMatch(bestOf int) *Match {
+	switch bestOf {
+	case 3, 5, 7:
+	default:
+		bestOf = 1
+	}
+	return &Match{
+		BestOf:  bestOf,
+		Game:    1,
+		Starter: "R",
+		Score:   map[string]int{"R": 0, "Y": 0},
+	}
+}
+
+// record compte le résultat de la manche terminée (une seule fois par manche)
+func (m *Match) record(st game.GameState) {
+	if m == nil || !st.Finished || m.Recorded {
+		return
+	}
+	m.Recorded = true
+	if st.Winner == "" {
+		m.Draws++
+	} else {
+		m.Score[st.Winner]++
+	}
+	if m.BestOf > 1 {
+		for team, wins := range m.Score {
+			if wins > m.BestOf/2 {
+				m.Winner = team
+				m.Finished = true
+			}
+		}
+	}
+}
+
+// startNextGame remet le plateau à zéro pour la manche suivante en gardant les connexions.
+// Celui qui commence change à chaque manche, dans l'ordre de jeu. p.Mu doit être verrouillé.
+func (p *Party) startNextGame() {
+	m := p.Match
+	previousStarter := m.Starter
+	if m.Finished {
+		// Nouvelle série entre les mêmes joueurs
+		*m = *newMatch(m.BestOf)
+		m.Game = 0
+	}
+	m.Game++
+	m.RematchOffer = ""
+	m.Recorded = false
+
+	st := game.GameState{
+		Rows:        p.State.Rows,
+		Cols:        p.State.Cols,
+		WinLength:   p.State.WinLength,
+		Mode:        p.State.Mode,
+		Variant:     p.State.Variant,
+		Options:     p.State.Options,
+		Players:     p.State.Players,
+		Elimination: p.State.Elimination,
+		Template:    p.State.Template,
+		Version:     p.State.Version + 1, // La version continue d'augmenter pour que les clients se rafraîchissent
+	}
+	st.Next = game.NextPlayer(st, previousStarter)
+	m.Starter = st.Next
+	p.cancelRound()
+	p.State = st
+	p.initBoard()
+	p.DoublePlayNext = false
+	p.BlockedColumn = -1
+	p.Boosters = nil
+	p.Teams.newGame(m.Starter)
+	p.Log = append(p.Log, LogEntry{At: time.Now(), Kind: "rematch", Team: m.Starter, Text: "Manche suivante"})
+}
+
+// handleRematch gère le cycle proposition / acceptation / refus de revanche
+func handleRematch(p *Party, conn *websocket.Conn, action string) {
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	team := seatColor(p.ClientTeam[conn]) // En 2 contre 2, les partenaires décident pour leur équipe
+	isSolo := p.Solo
+	if team == "" {
+		_ = conn.WriteJSON(map[string]interface{}{"type": "error", "message": "Seuls les joueurs peuvent demander une revanche"})
+		return
+	}
+	if !p.State.Finished {
+		_ = conn.WriteJSON(map[string]interface{}{"type": "error", "message": "La manche n'est pas terminée"})
+		return
+	}
+
+	m := p.Match
+	switch action {
+	case "rematch-offer":
+		m.RematchOffer = team
+		// En solo les deux joueurs partagent l'écran : la revanche démarre tout de suite
+		if !isSolo {
+			p.changed()
+			p.broadcast(map[string]interface{}{"type": "rematch", "status": "offered", "team": team, "match": m})
+			return
+		}
+	case "rematch-accept":
+		if m.RematchOffer == "" || (m.RematchOffer == team && !isSolo) {
+			return
+		}
+	case "rematch-decline":
+		if m.RematchOffer == "" {
+			return
+		}
+		m.RematchOffer = ""
+		p.changed()
+		p.broadcast(map[string]interface{}{"type": "rematch", "status": "declined", "team": team, "match": m})
+		return
+	}
+
+	p.startNextGame()
+	p.changed()
+	p.logger().Info("revanche", "seat", team, "game", m.Game)
+	p.broadcast(map[string]interface{}{"type": "rematch", "status": "accepted", "team": team, "match": m})
+	p.broadcastState(map[string]interface{}{"type": "state", "blocked": p.BlockedColumn})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"power4/game"
+	"testing"
+)
+
+func TestMatchRecord(t *testing.T) {
+	m := newMatch(3)
+	won := game.GameState{Finished: true, Winner: "R"}
+	m.record(won)
+	m.record(won) // Une seule fois par manche
+	if m.Score["R"] != 1 || m.Finished {
+		t.Fatalf("après une victoire : score=%v finished=%v", m.Score, m.Finished)
+	}
+
+	m.Recorded = false
+	m.record(game.GameState{Finished: true})
+	if m.Draws != 1 || m.Score["R"] != 1 || m.Score["Y"] != 0 {
+		t.Fatalf("une nulle ne compte pour personne : score=%v nulles=%d", m.Score, m.Draws)
+	}
+	m.Recorded = false
+	m.record(game.GameState{Finished: false, Winner: "Y"})
+	if m.Score["Y"] != 0 {
+		t.Fatal("une manche en cours ne compte pas")
+	}
+
+	m.Recorded = false
+	m.record(won)
+	if !m.Finished || m.Winner != "R" || m.Score["R"] != 2 {
+		t.Fatalf("série au meilleur de 3 : score=%v finished=%v winner=%q", m.Score, m.Finished, m.Winner)
+	}
+
+	free := newMatch(4) // Valeur non prévue : revanches libres
+	for i := 0; i < 5; i++ {
+		free.Recorded = false
+		free.record(won)
+	}
+	if free.BestOf != 1 || free.Finished {
+		t.Fatalf("revanches libres : bestOf=%d finished=%v", free.BestOf, free.Finished)
+	}
+}
+
+func TestStartNextGameAlternatesStarter(t *testing.T) {
+	p := createParty(t, "variant=classique&bestof=3")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	finish := func(winner string) {
+		p.State.Finished, p.State.Winner = true, winner
+		p.changed()
+		p.startNextGame()
+	}
+
+	finish("R")
+	if p.Match.Game != 2 || p.State.Next != "Y" || p.Match.Starter != "Y" || p.State.Finished {
+		t.Fatalf("manche 2 : game=%d next=%s", p.Match.Game, p.State.Next)
+	}
+	finish("")
+	if p.Match.Game != 3 || p.State.Next != "R" || p.Match.Draws != 1 {
+		t.Fatalf("manche 3 : game=%d next=%s nulles=%d", p.Match.Game, p.State.Next, p.Match.Draws)
+	}
+	p.State.Finished, p.State.Winner = true, "R"
+	p.changed()
+	if !p.Match.Finished || p.Match.Winner != "R" {
+		t.Fatalf("série : finished=%v winner=%q score=%v", p.Match.Finished, p.Match.Winner, p.Match.Score)
+	}
+
+	// Revanche après une série terminée : nouvelle série, l'autre joueur commence
+	p.startNextGame()
+	if p.Match.Finished || p.Match.Game != 1 || p.Match.Score["R"] != 0 || p.State.Next != "Y" {
+		t.Fatalf("nouvelle série : game=%d score=%v next=%s", p.Match.Game, p.Match.Score, p.State.Next)
+	}
+}
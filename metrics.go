@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ---------------- MÉTRIQUES PROMETHEUS ----------------
+// Exposées sur /metrics au format texte de Prometheus (version 0.0.4), sans dépendance externe.
+
+// counterVec : compteur avec une étiquette (mode, type de booster, raison...)
+type counterVec struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func newCounterVec() *counterVec { return &counterVec{values: make(map[string]float64)} }
+
+func (c *counterVec) inc(label string) {
+	c.mu.Lock()
+	c.values[label]++
+	c.mu.Unlock()
+}
+
+func (c *counterVec) snapshot() map[string]float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]float64, len(c.values))
+	for k, v := range c.values {
+		out[k] = v
+	}
+	return out
+}
+
+// histogram : répartition de durées en secondes, buckets cumulés à l'écriture
+type histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets ...float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	v := d.Seconds()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+var (
+	metricPartiesCreated = newCounterVec() // par mode
+	metricPartyJoins     = newCounterVec() // par mode
+	metricPartyFailures  = newCounterVec() // par raison
+	metricMoves          = newCounterVec() // par mode
+	metricBoosterUses    = newCounterVec() // par type de booster
+	metricRateLimited    = newCounterVec() // par action
+	metricWSClients      atomic.Int64
+
+	metricGameDuration = newHistogram(5, 10, 30, 60, 120, 300, 600, 1800, 3600, 86400, 604800)
+	metricBroadcast    = newHistogram(0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25)
+)
+
+// escapeLabel échappe une valeur d'étiquette selon le format d'exposition
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeVec(w io.Writer, name, kind, help, label string, values map[string]float64) {
+	writeHeader(w, name, kind, help)
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %s\n", name, label, escapeLabel(k), formatFloat(values[k]))
+	}
+}
+
+func writeHistogram(w io.Writer, name, help string, h *histogram) {
+	writeHeader(w, name, "histogram", help)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var cumulative uint64
+	for i, b := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, formatFloat(b), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// activePartiesByMode compte les parties dont la manche en cours n'est pas terminée
+func activePartiesByMode() (active map[string]float64, total int) {
+	partiesMu.Lock()
+	list := make([]*Party, 0, len(parties))
+	for _, p := range parties {
+		list = append(list, p)
+	}
+	partiesMu.Unlock()
+
+	active = make(map[string]float64)
+	for _, p := range list {
+		p.Mu.Lock()
+		if !p.State.Finished {
+			active[p.State.Mode]++
+		}
+		p.Mu.Unlock()
+	}
+	return active, len(list)
+}
+
+// writeMetrics écrit toutes les métriques du serveur
+func writeMetrics(w io.Writer) {
+	active, total := activePartiesByMode()
+	writeVec(w, "power4_parties_active", "gauge", "Parties dont la manche en cours n'est pas terminée, par mode.", "mode", active)
+	writeHeader(w, "power4_parties", "gauge", "Nombre de parties dans la table des parties du serveur.")
+	fmt.Fprintf(w, "power4_parties %d\n", total)
+	writeHeader(w, "power4_websocket_clients", "gauge", "Connexions WebSocket ouvertes.")
+	fmt.Fprintf(w, "power4_websocket_clients %d\n", metricWSClients.Load())
+	writeVec(w, "power4_moves_total", "counter", "Coups joués, par mode (rate() donne les coups par seconde).", "mode", metricMoves.snapshot())
+	writeVec(w, "power4_booster_uses_total", "counter", "Boosters utilisés, par type.", "type", metricBoosterUses.snapshot())
+	writeVec(w, "power4_parties_created_total", "counter", "Parties créées, par mode.", "mode", metricPartiesCreated.snapshot())
+	writeVec(w, "power4_party_joins_total", "counter", "Joueurs ayant rejoint une partie, par mode.", "mode", metricPartyJoins.snapshot())
+	writeVec(w, "power4_party_failures_total", "counter", "Échecs de création, de connexion ou d'accès à une partie, par raison.", "reason", metricPartyFailures.snapshot())
+	writeVec(w, "power4_rate_limited_total", "counter", "Requêtes refusées par les limites de débit, par action.", "action", metricRateLimited.snapshot())
+	writeHistogram(w, "power4_game_duration_seconds", "Durée des manches terminées.", metricGameDuration)
+	writeHistogram(w, "power4_broadcast_duration_seconds", "Temps de diffusion d'un état à tous les clients d'une partie.", metricBroadcast)
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w)
+}
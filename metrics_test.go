@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistogramExposition(t *testing.T) {
+	h := newHistogram(1, 5)
+	h.observe(500 * time.Millisecond)
+	h.observe(2 * time.Second)
+	h.observe(time.Minute)
+	var buf bytes.Buffer
+	writeHistogram(&buf, "x_seconds", "Test.", h)
+	for _, line := range []string{
+		`x_seconds_bucket{le="1"} 1`,
+		`x_seconds_bucket{le="5"} 2`,
+		`x_seconds_bucket{le="+Inf"} 3`,
+		`x_seconds_sum 62.5`,
+		`x_seconds_count 3`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("ligne %q absente de :\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestCounterLabelsEscaped(t *testing.T) {
+	c := newCounterVec()
+	c.inc(`a"b`)
+	var buf bytes.Buffer
+	writeVec(&buf, "y_total", "counter", "Test.", "mode", c.snapshot())
+	if !strings.Contains(buf.String(), `y_total{mode="a\"b"} 1`) {
+		t.Fatalf("étiquette mal échappée :\n%s", buf.String())
+	}
+}
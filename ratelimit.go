@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---------------- ORIGINES ET LIMITES DE DÉBIT ----------------
+// Seaux à jetons par adresse IP (création, arrivée, coups, boosters) et par session
+// (connexion WebSocket ou siège) pour les coups et les boosters. Limites dans cfg.RateLimits.
+
+// wsReadLimit : taille maximale d'un message WebSocket reçu (octets)
+const wsReadLimit = 4096
+
+// bucket : seau à jetons d'une clé
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter : seaux à jetons indexés par clé (adresse IP, session...)
+type limiter struct {
+	mu        sync.Mutex
+	limit     rateLimit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newLimiter(l rateLimit) *limiter {
+	return &limiter{limit: l, buckets: make(map[string]*bucket)}
+}
+
+// allow consomme un jeton pour key ; sinon retourne l'attente avant le prochain jeton
+func (l *limiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.limit.Rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep oublie, au plus une fois par minute, les seaux redevenus pleins. l.mu doit être verrouillé.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// actionLimits : limites d'une action, par adresse IP et éventuellement par session
+type actionLimits struct {
+	name    string
+	ip      *limiter
+	session *limiter // nil si l'action n'est limitée que par adresse IP
+	tooFast string
+}
+
+func newActionLimits(name string, l rateLimit, ipFactor float64, perSession bool, tooFast string) *actionLimits {
+	a := &actionLimits{name: name, tooFast: tooFast, ip: newLimiter(l)}
+	if perSession {
+		a.session = newLimiter(l)
+		// Plusieurs joueurs peuvent partager une adresse IP
+		a.ip = newLimiter(rateLimit{Rate: l.Rate * ipFactor, Burst: int(float64(l.Burst) * ipFactor)})
+	}
+	return a
+}
+
+// check retourne une erreur si l'adresse ip ou la session ont dépassé leur limite
+func (a *actionLimits) check(ip, session string) error {
+	now := time.Now()
+	ok, wait := a.ip.allow(ip, now)
+	if ok && a.session != nil && session != "" {
+		ok, wait = a.session.allow(session, now)
+	}
+	if ok {
+		return nil
+	}
+	metricRateLimited.inc(a.name)
+	return &rateLimitError{message: a.tooFast, retry: wait}
+}
+
+// rateLimitError : action refusée, à retenter après retry
+type rateLimitError struct {
+	message string
+	retry   time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("%s (réessayez dans %ds)", e.message, e.seconds())
+}
+
+func (e *rateLimitError) seconds() int { return int(math.Ceil(e.retry.Seconds())) }
+
+// writeRateLimited répond 429 avec Retry-After
+func writeRateLimited(w http.ResponseWriter, r *http.Request, err error) {
+	if rl, ok := err.(*rateLimitError); ok {
+		w.Header().Set("Retry-After", fmt.Sprint(rl.seconds()))
+	}
+	requestLogger(r).Warn("limite de débit atteinte", "path", r.URL.Path, "ip", clientIP(r))
+	writeJSONError(w, http.StatusTooManyRequests, err.Error())
+}
+
+// rateLimiters : limites de chaque action, reconstruites au démarrage depuis cfg
+type rateLimiters struct {
+	create, join, move, booster *actionLimits
+}
+
+func newRateLimiters(c rateLimits) *rateLimiters {
+	return &rateLimiters{
+		create:  newActionLimits("create", c.Create, c.IPFactor, false, "Trop de parties créées"),
+		join:    newActionLimits("join", c.Join, c.IPFactor, false, "Trop de connexions"),
+		move:    newActionLimits("move", c.Move, c.IPFactor, true, "Trop de coups, ralentissez"),
+		booster: newActionLimits("booster", c.Booster, c.IPFactor, true, "Trop de boosters utilisés"),
+	}
+}
+
+var limits = newRateLimiters(cfg.RateLimits)
+
+// clientIP retourne l'adresse du client, lue dans X-Forwarded-For si cfg.TrustProxy
+func clientIP(r *http.Request) string {
+	if cfg.TrustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// originAllowed accepte les clients sans en-tête Origin (CLI, robots), la même origine
+// que le serveur et les origines de cfg.AllowedOrigins ("*" pour toutes).
+func originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), u.Scheme+"://"+u.Host) {
+			return true
+		}
+	}
+	return false
+}
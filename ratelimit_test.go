@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLimiterRefillsAtRate(t *testing.T) {
+	l := newLimiter(rateLimit{Rate: 2, Burst: 3})
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow("a", now); !ok {
+			t.Fatalf("jeton %d refusé dans la réserve", i+1)
+		}
+	}
+	ok, wait := l.allow("a", now)
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("seau vide : ok=%v wait=%v, attendu refus et 500ms", ok, wait)
+	}
+	if ok, _ := l.allow("b", now); !ok {
+		t.Fatal("les clés doivent avoir des seaux séparés")
+	}
+	if ok, _ := l.allow("a", now.Add(500*time.Millisecond)); !ok {
+		t.Fatal("un jeton doit revenir après 1/rate seconde")
+	}
+}
+
+func TestActionLimitsPerSession(t *testing.T) {
+	a := newActionLimits("move", rateLimit{Rate: 1, Burst: 2}, 2, true, "Trop de coups")
+	for i := 0; i < 2; i++ {
+		if err := a.check("1.2.3.4", "s1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.check("1.2.3.4", "s1"); err == nil {
+		t.Fatal("session au-delà de sa limite acceptée")
+	}
+	// Même adresse, autre session : la marge par adresse IP le permet
+	if err := a.check("1.2.3.4", "s2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	defer func(saved []string) { cfg.AllowedOrigins = saved }(cfg.AllowedOrigins)
+	cfg.AllowedOrigins = []string{"https://puissance4.example"}
+	cases := map[string]bool{
+		"":                           true,
+		"http://jeu.local":           true, // Même origine que l'hôte de la requête
+		"https://puissance4.example": true,
+		"https://evil.example":       false,
+	}
+	for origin, want := range cases {
+		r := httptest.NewRequest(http.MethodGet, "http://jeu.local/ws/ABC", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if got := originAllowed(r); got != want {
+			t.Errorf("origine %q : %v, attendu %v", origin, got, want)
+		}
+	}
+}
+
+func TestBoosterLimitIgnoresPlayerName(t *testing.T) {
+	limits = newRateLimiters(defaultConfig().RateLimits)
+	t.Cleanup(func() { limits = newRateLimiters(defaultConfig().RateLimits) })
+	p := createParty(t, "variant=turbo")
+	burst := defaultConfig().RateLimits.Booster.Burst
+	for i := 0; i <= burst; i++ {
+		// Un nom de joueur différent à chaque appel ne donne pas de nouveau seau
+		w := postBooster(url.Values{"code": {p.Code}, "player": {fmt.Sprint("joueur", i)}, "action": {"double-shot"}})
+		if limited := w.Code == http.StatusTooManyRequests; limited != (i == burst) {
+			t.Fatalf("appel %d : code %d", i+1, w.Code)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	mrand "math/rand"
+	"power4/game"
+	"slices"
+	"time"
+)
+
+// ---------------- COUPS SIMULTANÉS (BLITZ) ----------------
+
+var errAlreadyPicked = errors.New("Colonne déjà choisie pour ce tour")
+
+// round : tour en cours d'une variante à coups simultanés. Les colonnes choisies restent
+// secrètes jusqu'à la résolution du tour.
+type round struct {
+	Picks map[string]int // Colonne choisie par couleur
+	Ends  time.Time      // Fin du compte à rebours, lancé par le premier choix
+	timer *time.Timer
+}
+
+// isSimultaneous indique si les coups de la partie se jouent par tours simultanés (game.PlayRound)
+func (p *Party) isSimultaneous() bool {
+	_, ok := game.VariantOf(p.State).(game.Simultaneous)
+	return ok
+}
+
+// pickColumn enregistre la colonne choisie par l'équipe team pour le tour en cours. Le premier
+// choix lance le compte à rebours ; le tour est résolu dès que tous les joueurs ont choisi,
+// ou à la fin du temps. p.Mu doit être verrouillé.
+func (p *Party) pickColumn(team string, col int) error {
+	color := seatColor(team)
+	switch {
+	case p.State.Finished || !slices.Contains(game.LegalColumns(p.State), col):
+		return errIllegalMove
+	case !contains(game.PlayersOf(p.State), color):
+		return errNotYourTurn
+	case p.Round != nil:
+		if _, done := p.Round.Picks[color]; done {
+			return errAlreadyPicked
+		}
+	default:
+		d := game.VariantOf(p.State).(game.Simultaneous).RoundTime(p.State)
+		rd := &round{Picks: make(map[string]int), Ends: time.Now().Add(d)}
+		rd.timer = time.AfterFunc(d, func() {
+			p.Mu.Lock()
+			defer p.Mu.Unlock()
+			if p.Round == rd {
+				p.resolveRound()
+			}
+		})
+		p.Round = rd
+	}
+	p.Round.Picks[color] = col
+	p.logger().Debug("colonne choisie", "seat", team, "col", col)
+	if len(p.Round.Picks) == len(game.PlayersOf(p.State)) {
+		p.resolveRound()
+		return nil
+	}
+	// Les autres apprennent qu'un choix est fait, pas la colonne
+	p.broadcast(map[string]interface{}{"type": "pick", "team": color, "ends": p.Round.Ends.UnixMilli()})
+	return nil
+}
+
+// resolveRound fait tomber les pions du tour en cours et diffuse le nouvel état. p.Mu doit être verrouillé.
+func (p *Party) resolveRound() {
+	rd := p.Round
+	p.cancelRound()
+	if rd == nil || p.State.Finished {
+		return // Manche terminée entre-temps (administration)
+	}
+	res, err := game.PlayRound(&p.State, rd.Picks, mrand.New(mrand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		p.logger().Warn("tour impossible à résoudre", "err", err)
+		return
+	}
+	for _, pl := range res.Placed {
+		p.Log = append(p.Log, LogEntry{At: time.Now(), Kind: "move", Team: pl.Player, Row: pl.Row, Col: pl.Col})
+		metricMoves.inc(p.State.Mode)
+	}
+	p.State.Version++
+	p.logger().Debug("tour résolu", "picks", len(rd.Picks), "placed", len(res.Placed), "coin", res.Coin)
+	if p.State.Finished {
+		p.logger().Info("manche terminée", "winner", p.State.Winner)
+	}
+	p.changed()
+	p.broadcastState(map[string]interface{}{"type": "state", "blocked": p.BlockedColumn, "round": res})
+}
+
+// cancelRound abandonne le tour en cours et son compte à rebours. p.Mu doit être verrouillé.
+func (p *Party) cancelRound() {
+	if p.Round != nil {
+		p.Round.timer.Stop()
+		p.Round = nil
+	}
+}
+
+// roundPicks retourne les couleurs ayant déjà choisi leur colonne pour le tour en cours
+// et la fin du compte à rebours (zéro sans tour en cours). p.Mu doit être verrouillé.
+func (p *Party) roundPicks() ([]string, time.Time) {
+	if p.Round == nil {
+		return nil, time.Time{}
+	}
+	var picked []string
+	for _, color := range game.PlayersOf(p.State) {
+		if _, ok := p.Round.Picks[color]; ok {
+			picked = append(picked, color)
+		}
+	}
+	return picked, p.Round.Ends
+}
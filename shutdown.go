@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+)
+
+// ---------------- ARRÊT PROPRE ----------------
+// Sur SIGINT ou SIGTERM, le serveur refuse les nouvelles parties, prévient les clients,
+// sauvegarde toutes les parties en cours puis ferme les WebSockets (code 1012, « Service
+// Restart ») avant de s'arrêter, au plus tard après cfg.ShutdownTimeout.
+
+// draining passe à vrai dès le début de l'arrêt
+var draining atomic.Bool
+
+const restartMessage = "Le serveur redémarre, reconnexion dans quelques instants…"
+
+// refuseWhileDraining répond 503 si le serveur est en cours d'arrêt
+func refuseWhileDraining(w http.ResponseWriter) bool {
+	if !draining.Load() {
+		return false
+	}
+	metricPartyFailures.inc("draining")
+	w.Header().Set("Retry-After", "5")
+	writeJSONError(w, http.StatusServiceUnavailable, "Le serveur redémarre, réessayez dans quelques instants")
+	return true
+}
+
+// drainParties prévient les clients, sauvegarde les parties en cours et ferme
+// leurs connexions. Les parties non terminées sont rechargées au démarrage suivant.
+func drainParties() (saved int) {
+	for _, p := range allParties() {
+		p.Mu.Lock()
+		p.broadcast(map[string]interface{}{"type": "announcement", "text": restartMessage})
+		if p.Async || !p.State.Finished {
+			if err := saveParty(p); err != nil {
+				p.logger().Error("sauvegarde de la partie impossible", "err", err)
+			} else {
+				saved++
+			}
+		}
+		for c := range p.Clients {
+			closeConn(c, websocket.CloseServiceRestart, "Le serveur redémarre")
+		}
+		p.Mu.Unlock()
+	}
+	return saved
+}
+
+// serve lance srv jusqu'à la réception d'un signal d'arrêt, puis l'arrête proprement
+func serve(srv *http.Server) error {
+	errc := make(chan error, 1)
+	go func() {
+		if cfg.TLS.Cert != "" {
+			errc <- srv.ListenAndServeTLS(cfg.TLS.Cert, cfg.TLS.Key)
+			return
+		}
+		errc <- srv.ListenAndServe()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	stop() // Un second signal interrompt immédiatement le processus
+
+	logger.Info("arrêt du serveur demandé", "timeout", cfg.ShutdownTimeout.Duration)
+	draining.Store(true)
+	deadline, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout.Duration)
+	defer cancel()
+
+	done := make(chan int, 1)
+	go func() { done <- drainParties() }()
+	select {
+	case saved := <-done:
+		logger.Info("parties sauvegardées", "count", saved)
+	case <-deadline.Done():
+		logger.Error("délai d'arrêt dépassé pendant la sauvegarde des parties")
+	}
+
+	// Les connexions WebSocket détournées ne sont pas suivies par Shutdown : elles sont déjà fermées
+	if err := srv.Shutdown(deadline); err != nil {
+		_ = srv.Close()
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
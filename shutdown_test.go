@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"power4/game"
+	"testing"
+)
+
+func TestDrainPartiesSavesUnfinished(t *testing.T) {
+	defer func(saved string) { dataDir = saved }(dataDir)
+	dataDir = t.TempDir()
+
+	running := newParty("RUN001", game.GameState{Rows: 6, Cols: 7, WinLength: 4, Next: "R", Mode: "multi-classique"})
+	done := newParty("END001", game.GameState{Rows: 6, Cols: 7, WinLength: 4, Finished: true, Mode: "multi-classique"})
+	partiesMu.Lock()
+	parties[running.Code], parties[done.Code] = running, done
+	partiesMu.Unlock()
+	defer func() {
+		partiesMu.Lock()
+		delete(parties, running.Code)
+		delete(parties, done.Code)
+		partiesMu.Unlock()
+	}()
+
+	if n := drainParties(); n != 1 {
+		t.Fatalf("%d parties sauvegardées, attendu 1", n)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "RUN001.json")); err != nil {
+		t.Fatalf("partie en cours non sauvegardée : %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "END001.json")); !os.IsNotExist(err) {
+		t.Fatalf("partie terminée sauvegardée inutilement : %v", err)
+	}
+}
+
+func TestCreateRefusedWhileDraining(t *testing.T) {
+	draining.Store(true)
+	defer draining.Store(false)
+	w := httptest.NewRecorder()
+	createPartyHandler(w, httptest.NewRequest(http.MethodGet, "/api/party/create", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("code %d, attendu 503", w.Code)
+	}
+}
+
+func TestMovesRefusedWhileDraining(t *testing.T) {
+	p, token := newAsyncParty(t)
+	version := p.State.Version
+	draining.Store(true)
+	defer draining.Store(false)
+	if w := postMove(p.Code, token, 3); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("coup : code %d, attendu 503", w.Code)
+	}
+	w := postBooster(url.Values{"code": {p.Code}, "token": {token}, "action": {"double-shot"}})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("booster : code %d, attendu 503", w.Code)
+	}
+	if p.State.Version != version || p.State.Board[p.State.Rows-1][3] != "" {
+		t.Fatal("partie modifiée pendant l'arrêt")
+	}
+}
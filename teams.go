@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"power4/game"
+	"strings"
+)
+
+// ---------------- MODE 2 CONTRE 2 ----------------
+// Quatre sièges en deux équipes de couleur : R1 et R2 jouent les pions rouges, Y1 et Y2
+// les jaunes. Les partenaires jouent à tour de rôle dans l'ordre choisi à la création
+// (R1, Y1, R2, Y2 par défaut). La manche est gagnée par l'équipe ; les statistiques sont
+// tenues par siège.
+
+// teamSeats : sièges du mode 2 contre 2, dans l'ordre de jeu par défaut
+var teamSeats = []string{"R1", "Y1", "R2", "Y2"}
+
+var errTeamOrder = errors.New("Ordre de jeu invalide : les quatre sièges R1, R2, Y1, Y2 en alternant les couleurs")
+
+// SeatStats : statistiques d'un siège sur toutes les manches de la partie
+type SeatStats struct {
+	Moves    int `json:"moves"`
+	Boosters int `json:"boosters"` // Boosters ramassés
+	Winning  int `json:"winning"`  // Coups gagnants joués
+	Wins     int `json:"wins"`     // Manches gagnées par l'équipe
+	Losses   int `json:"losses"`
+	Draws    int `json:"draws"`
+}
+
+// Teams : état du mode 2 contre 2 d'une partie
+type Teams struct {
+	Order    []string              `json:"order"`    // Ordre de jeu des sièges
+	Turn     int                   `json:"turn"`     // Index dans Order du siège au trait
+	Stats    map[string]*SeatStats `json:"stats"`    // Siège -> statistiques
+	Recorded bool                  `json:"recorded"` // Résultat de la manche en cours déjà compté
+}
+
+// parseTeamOrder lit un ordre "R1,Y1,R2,Y2" ; vide donne l'ordre par défaut
+func parseTeamOrder(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return append([]string(nil), teamSeats...), nil
+	}
+	order := splitList(strings.ToUpper(s))
+	if len(order) != len(teamSeats) {
+		return nil, errTeamOrder
+	}
+	seen := map[string]bool{}
+	for i, seat := range order {
+		if !contains(teamSeats, seat) || seen[seat] {
+			return nil, errTeamOrder
+		}
+		seen[seat] = true
+		// Les couleurs alternent : game.Play passe le trait à l'autre couleur après chaque coup
+		if i > 0 && seatColor(seat) == seatColor(order[i-1]) {
+			return nil, errTeamOrder
+		}
+	}
+	return order, nil
+}
+
+// newTeams prépare le mode 2 contre 2 ; le premier siège de la couleur next a le trait
+func newTeams(order []string, next string) *Teams {
+	t := &Teams{Order: order, Turn: len(order) - 1, Stats: make(map[string]*SeatStats)}
+	for _, seat := range order {
+		t.Stats[seat] = &SeatStats{}
+	}
+	t.advance(next)
+	return t
+}
+
+// seatColor retourne la couleur ("R", "Y", "G" ou "B") d'un siège ("R", "Y2"...), "" pour un spectateur
+func seatColor(seat string) string {
+	if seat != "" && contains(game.PlayerTokens, seat[:1]) {
+		return seat[:1]
+	}
+	return ""
+}
+
+// isSeat indique si seat est un des quatre sièges
+func (t *Teams) isSeat(seat string) bool {
+	return contains(t.Order, seat)
+}
+
+// current retourne le siège au trait
+func (t *Teams) current() string {
+	return t.Order[t.Turn]
+}
+
+// advance passe au siège suivant de la couleur next dans l'ordre de jeu
+func (t *Teams) advance(next string) {
+	for i := 1; i <= len(t.Order); i++ {
+		if j := (t.Turn + i) % len(t.Order); seatColor(t.Order[j]) == next {
+			t.Turn = j
+			return
+		}
+	}
+}
+
+// played compte le coup du siège au trait et passe la main si la couleur au trait a changé
+func (t *Teams) played(booster string, st game.GameState) {
+	seat := t.current()
+	s := t.Stats[seat]
+	s.Moves++
+	if booster != "" {
+		s.Boosters++
+	}
+	if st.Finished && st.Winner == seatColor(seat) {
+		s.Winning++
+	}
+	if !st.Finished && st.Next != seatColor(seat) {
+		t.advance(st.Next)
+	}
+}
+
+// record compte le résultat de la manche terminée pour chaque siège (une seule fois par manche)
+func (t *Teams) record(st game.GameState) {
+	if t == nil || !st.Finished || t.Recorded {
+		return
+	}
+	t.Recorded = true
+	for seat, s := range t.Stats {
+		switch st.Winner {
+		case "":
+			s.Draws++
+		case seatColor(seat):
+			s.Wins++
+		default:
+			s.Losses++
+		}
+	}
+}
+
+// newGame prépare la manche suivante : le trait passe au siège suivant de la couleur next
+func (t *Teams) newGame(next string) {
+	if t == nil {
+		return
+	}
+	t.Recorded = false
+	t.advance(next)
+}
+
+// syncTeams remet le siège au trait sur la couleur au trait quand le trait change hors de
+// game.Play (joker). p.Mu doit être verrouillé.
+func (p *Party) syncTeams() {
+	if p.Teams != nil && !p.State.Finished && seatColor(p.Teams.current()) != p.State.Next {
+		p.Teams.advance(p.State.Next)
+	}
+}
+
+// turnSeat retourne le siège qui doit jouer : un des quatre sièges en 2 contre 2, sinon la couleur
+func (p *Party) turnSeat() string {
+	if p.Teams != nil {
+		return p.Teams.current()
+	}
+	return p.State.Next
+}
+
+// seatList retourne les sièges de la partie
+func (p *Party) seatList() []string {
+	if p.Teams != nil {
+		return p.Teams.Order
+	}
+	return game.PlayersOf(p.State)
+}
+
+// connectedSeat choisit le siège d'un client qui demande l'équipe team : un siège exact,
+// ou pour une couleur seule le premier siège de cette couleur sans client connecté.
+// Sans siège disponible, le client est spectateur. p.Mu doit être verrouillé.
+func (p *Party) connectedSeat(team string) string {
+	if p.Teams == nil || p.Teams.isSeat(team) {
+		return team
+	}
+	taken := map[string]bool{}
+	for _, seat := range p.ClientTeam {
+		taken[seat] = true
+	}
+	for _, seat := range p.Teams.Order {
+		if seatColor(seat) == team && !taken[seat] {
+			return seat
+		}
+	}
+	return "S"
+}
+
+// seatView : ligne du tableau des sièges affiché en 2 contre 2
+type seatView struct {
+	Seat  string
+	Color string
+	Name  string
+	Turn  bool
+	Stats SeatStats
+}
+
+// teamView prépare le tableau des sièges pour la page de jeu. p.Mu doit être verrouillé.
+func (p *Party) teamView() []seatView {
+	if p.Teams == nil {
+		return nil
+	}
+	var rows []seatView
+	for _, seat := range p.Teams.Order {
+		rows = append(rows, seatView{
+			Seat:  seat,
+			Color: seatColor(seat),
+			Name:  p.seatName(seat),
+			Turn:  seat == p.Teams.current() && !p.State.Finished,
+			Stats: *p.Teams.Stats[seat],
+		})
+	}
+	return rows
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"power4/game"
+	"reflect"
+	"testing"
+)
+
+func TestParseTeamOrder(t *testing.T) {
+	order, err := parseTeamOrder("")
+	if err != nil || !reflect.DeepEqual(order, []string{"R1", "Y1", "R2", "Y2"}) {
+		t.Fatalf("ordre par défaut : %v, %v", order, err)
+	}
+	if order, err := parseTeamOrder("y2, r1,Y1,R2"); err != nil || order[0] != "Y2" {
+		t.Fatalf("ordre personnalisé : %v, %v", order, err)
+	}
+	for _, s := range []string{"R1,R2,Y1,Y2", "R1,Y1,R2", "R1,Y1,R1,Y2", "R1,Y1,R3,Y2"} {
+		if _, err := parseTeamOrder(s); err == nil {
+			t.Errorf("%q aurait dû être refusé", s)
+		}
+	}
+}
+
+func TestTeamsTurnOrderAndStats(t *testing.T) {
+	p := createParty(t, "variant=classique&teams=1&order=R1,Y2,R2,Y1")
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	// R1, Y2, R2, Y1 jouent à tour de rôle ; R aligne quatre pions dans la colonne 0
+	seats := []string{"R1", "Y2", "R2", "Y1", "R1", "Y2", "R2"}
+	cols := []int{0, 1, 0, 1, 0, 1, 0}
+	for i, seat := range seats {
+		if p.Teams.current() != seat {
+			t.Fatalf("coup %d : au trait %s, attendu %s", i, p.Teams.current(), seat)
+		}
+		if _, err := p.playColumn(game.Opponent(seat[:1])+"1", cols[i]); err != errNotYourTurn {
+			t.Fatalf("coup %d : siège adverse accepté (%v)", i, err)
+		}
+		if seat != "R1" && seat != "Y1" {
+			if _, err := p.playColumn(seat[:1]+"1", cols[i]); err != errNotYourTurn {
+				t.Fatalf("coup %d : partenaire accepté (%v)", i, err)
+			}
+		}
+		if _, err := p.playColumn(seat, cols[i]); err != nil {
+			t.Fatalf("coup %d de %s : %v", i, seat, err)
+		}
+	}
+	if !p.State.Finished || p.State.Winner != "R" {
+		t.Fatalf("manche non gagnée par les rouges : %+v", p.State.Winner)
+	}
+	s := p.Teams.Stats
+	if s["R1"].Moves != 2 || s["R2"].Moves != 2 || s["R2"].Winning != 1 || s["R1"].Winning != 0 {
+		t.Fatalf("coups par siège : R1=%+v R2=%+v", *s["R1"], *s["R2"])
+	}
+	if s["R1"].Wins != 1 || s["R2"].Wins != 1 || s["Y1"].Losses != 1 || s["Y2"].Losses != 1 {
+		t.Fatalf("victoire d'équipe mal comptée : %+v", s)
+	}
+
+	// Manche suivante : les jaunes commencent, avec le siège qui suit R2 dans l'ordre
+	p.startNextGame()
+	if p.State.Next != "Y" || p.Teams.current() != "Y1" {
+		t.Fatalf("manche suivante : next=%s siège=%s", p.State.Next, p.Teams.current())
+	}
+}
+
+func TestTeamsOnlyCurrentSeatPlays(t *testing.T) {
+	p := createParty(t, "variant=turbo&teams=1")
+	p.Mu.Lock()
+	p.State.BoosterCells = [15][15]string{}
+	// La couleur seule ne suffit pas : c'est le tour du siège R1
+	if _, err := p.playColumn("R", 0); err != errNotYourTurn {
+		p.Mu.Unlock()
+		t.Fatalf("couleur R acceptée à la place de R1 : %v", err)
+	}
+	p.Boosters = map[string][]string{"R": {"wildcard"}}
+	p.Mu.Unlock()
+
+	// Le joker passe le trait aux jaunes : le siège au trait suit
+	if w := postBooster(url.Values{"action": {"wildcard"}, "code": {p.Code}, "player": {"R"}, "row": {"5"}, "col": {"3"}}); w.Code != http.StatusOK {
+		t.Fatalf("joker : code %d (%s)", w.Code, w.Body)
+	}
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+	if p.State.Next != "Y" || p.Teams.current() != "Y1" {
+		t.Fatalf("après le joker : next=%s siège=%s", p.State.Next, p.Teams.current())
+	}
+	if _, err := p.playColumn("Y1", 0); err != nil {
+		t.Fatalf("coup de Y1 : %v", err)
+	}
+}
+
+func TestTeamsRefusedInSolo(t *testing.T) {
+	for _, q := range []string{"variant=classique&solo=1&teams=1", "variant=classique&teams=1&order=R1,R2,Y1,Y2"} {
+		expectCreateRejected(t, q)
+	}
+}
+
+func TestTeamChatNotReplayed(t *testing.T) {
+	p := createParty(t, "variant=classique&teams=1")
+	p.Log = append(p.Log,
+		LogEntry{Kind: "chat", Team: "R1", Text: "public"},
+		LogEntry{Kind: "chat", Team: "R2", Text: "on attaque à gauche", Scope: "team"},
+	)
+	lines := p.chatLines()
+	if len(lines) != 1 || lines[0].Text != "public" || lines[0].Color != "R" || lines[0].Name != "Rouge 1" {
+		t.Fatalf("historique du chat : %+v", lines)
+	}
+}
@@ -0,0 +1,141 @@
+package tournament
+
+import "sort"
+
+// Points attribués par résultat
+const (
+	WinPoints  = 1.0
+	DrawPoints = 0.5
+)
+
+// Standing : ligne du classement d'un joueur
+type Standing struct {
+	Rank            int     `json:"rank"`
+	Player          string  `json:"player"`
+	Points          float64 `json:"points"`
+	Played          int     `json:"played"`
+	Wins            int     `json:"wins"`
+	Draws           int     `json:"draws"`
+	Losses          int     `json:"losses"`
+	Byes            int     `json:"byes"`
+	HeadToHead      float64 `json:"headToHead"`      // Points marqués contre les joueurs à égalité
+	SonnebornBerger float64 `json:"sonnebornBerger"` // Somme des points des adversaires battus + moitié des adversaires annulés
+}
+
+// Standings calcule le classement. Départages dans l'ordre : points,
+// confrontation directe entre joueurs à égalité, Sonneborn-Berger, tête de série.
+func (t *Tournament) Standings() []Standing {
+	rows := make(map[string]*Standing, len(t.Players))
+	seed := make(map[string]int, len(t.Players))
+	for i, name := range t.Players {
+		rows[name] = &Standing{Player: name}
+		seed[name] = i
+	}
+	done := make([]*Pairing, 0, len(t.Pairings))
+	for _, p := range t.Pairings {
+		if p.Done {
+			done = append(done, p)
+		}
+	}
+
+	for _, p := range done {
+		a := rows[p.A]
+		if p.IsBye() {
+			a.Byes++
+			a.Points += WinPoints
+			continue
+		}
+		b := rows[p.B]
+		a.Played++
+		b.Played++
+		switch {
+		case p.Draw:
+			a.Draws++
+			b.Draws++
+			a.Points += DrawPoints
+			b.Points += DrawPoints
+		case p.Winner == p.A:
+			a.Wins++
+			b.Losses++
+			a.Points += WinPoints
+		default:
+			b.Wins++
+			a.Losses++
+			b.Points += WinPoints
+		}
+	}
+
+	// Sonneborn-Berger et confrontation directe (entre joueurs ayant le même nombre de points)
+	for _, p := range done {
+		if p.IsBye() {
+			continue
+		}
+		a, b := rows[p.A], rows[p.B]
+		tied := a.Points == b.Points
+		switch {
+		case p.Draw:
+			a.SonnebornBerger += b.Points * DrawPoints
+			b.SonnebornBerger += a.Points * DrawPoints
+			if tied {
+				a.HeadToHead += DrawPoints
+				b.HeadToHead += DrawPoints
+			}
+		case p.Winner == p.A:
+			a.SonnebornBerger += b.Points
+			if tied {
+				a.HeadToHead += WinPoints
+			}
+		default:
+			b.SonnebornBerger += a.Points
+			if tied {
+				b.HeadToHead += WinPoints
+			}
+		}
+	}
+
+	list := make([]Standing, 0, len(rows))
+	for _, name := range t.Players {
+		list = append(list, *rows[name])
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.HeadToHead != b.HeadToHead {
+			return a.HeadToHead > b.HeadToHead
+		}
+		if a.SonnebornBerger != b.SonnebornBerger {
+			return a.SonnebornBerger > b.SonnebornBerger
+		}
+		return seed[a.Player] < seed[b.Player]
+	})
+	for i := range list {
+		list[i].Rank = i + 1
+	}
+	return list
+}
+
+// Round : rencontres d'une ronde pour l'affichage du tableau
+type Round struct {
+	Number   int        `json:"number"`
+	Pairings []*Pairing `json:"pairings"`
+}
+
+// Bracket regroupe les rencontres par tableau puis par ronde
+func (t *Tournament) Bracket() map[string][]Round {
+	out := make(map[string][]Round)
+	for _, p := range t.Pairings {
+		key := p.Bracket
+		if key == BracketMain {
+			key = "main"
+		}
+		rounds := out[key]
+		if len(rounds) == 0 || rounds[len(rounds)-1].Number != p.Round {
+			rounds = append(rounds, Round{Number: p.Round})
+		}
+		rounds[len(rounds)-1].Pairings = append(rounds[len(rounds)-1].Pairings, p)
+		out[key] = rounds
+	}
+	return out
+}
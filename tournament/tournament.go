@@ -0,0 +1,434 @@
+// Package tournament gère les tournois : inscriptions, appariements par ronde
+// (élimination simple ou double, système suisse, toutes rondes), exempts (byes),
+// résultats et classement avec départages.
+package tournament
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Formats de tournoi disponibles
+const (
+	SingleElimination = "single"
+	DoubleElimination = "double"
+	Swiss             = "swiss"
+	RoundRobin        = "roundrobin"
+)
+
+// Tableaux d'un tournoi à élimination
+const (
+	BracketWinners = "W"  // Tableau principal (aucune défaite)
+	BracketLosers  = "L"  // Tableau des perdants (double élimination)
+	BracketFinal   = "GF" // Grande finale (double élimination)
+	BracketMain    = ""   // Suisse et toutes rondes
+)
+
+var (
+	ErrUnknownFormat   = errors.New("format de tournoi inconnu")
+	ErrAlreadyStarted  = errors.New("le tournoi a déjà commencé")
+	ErrNotStarted      = errors.New("le tournoi n'a pas commencé")
+	ErrTooFewPlayers   = errors.New("il faut au moins deux joueurs")
+	ErrDuplicatePlayer = errors.New("joueur déjà inscrit")
+	ErrUnknownPairing  = errors.New("appariement inconnu")
+	ErrAlreadyReported = errors.New("résultat déjà enregistré")
+	ErrBadWinner       = errors.New("le vainqueur ne joue pas cet appariement")
+)
+
+// Pairing : une rencontre entre deux joueurs. B vaut "" pour un exempt (bye).
+type Pairing struct {
+	ID        int    `json:"id"`
+	Round     int    `json:"round"`
+	Bracket   string `json:"bracket,omitempty"`
+	A         string `json:"a"`
+	B         string `json:"b"`
+	Winner    string `json:"winner,omitempty"`
+	Draw      bool   `json:"draw,omitempty"`
+	Done      bool   `json:"done"`
+	PartyCode string `json:"partyCode,omitempty"` // Partie jouée pour cette rencontre
+}
+
+// IsBye indique une rencontre sans adversaire
+func (p *Pairing) IsBye() bool { return p.B == "" }
+
+// Tournament : état complet d'un tournoi
+type Tournament struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Mode     string     `json:"mode"`   // Mode de jeu des parties (ex : multi-classique)
+	Format   string     `json:"format"` // single, double, swiss, roundrobin
+	Rounds   int        `json:"rounds"` // Nombre de rondes (suisse)
+	Players  []string   `json:"players"`
+	Pairings []*Pairing `json:"pairings"`
+	Round    int        `json:"round"` // Ronde en cours
+	Started  bool       `json:"started"`
+	Finished bool       `json:"finished"`
+	Champion string     `json:"champion,omitempty"`
+}
+
+// New crée un tournoi vide. rounds n'est utilisé que pour le système suisse
+// (0 = nombre de rondes usuel, log2 du nombre de joueurs arrondi au supérieur).
+func New(id, name, mode, format string, rounds int) (*Tournament, error) {
+	switch format {
+	case SingleElimination, DoubleElimination, Swiss, RoundRobin:
+	default:
+		return nil, ErrUnknownFormat
+	}
+	return &Tournament{ID: id, Name: name, Mode: mode, Format: format, Rounds: rounds}, nil
+}
+
+// Register inscrit un joueur (l'ordre d'inscription sert de tête de série)
+func (t *Tournament) Register(player string) error {
+	if t.Started {
+		return ErrAlreadyStarted
+	}
+	for _, p := range t.Players {
+		if p == player {
+			return ErrDuplicatePlayer
+		}
+	}
+	t.Players = append(t.Players, player)
+	return nil
+}
+
+// Start génère la première ronde (toutes les rondes pour un toutes rondes).
+// Retourne les rencontres à jouer.
+func (t *Tournament) Start() ([]*Pairing, error) {
+	if t.Started {
+		return nil, ErrAlreadyStarted
+	}
+	if len(t.Players) < 2 {
+		return nil, ErrTooFewPlayers
+	}
+	t.Started = true
+	if t.Format == Swiss && t.Rounds <= 0 {
+		t.Rounds = int(math.Ceil(math.Log2(float64(len(t.Players)))))
+	}
+	if t.Format == RoundRobin {
+		t.generateRoundRobin()
+		return t.Pending(), nil
+	}
+	return t.nextRound(), nil
+}
+
+// Report enregistre le résultat d'une rencontre (winner vide = nulle) et génère
+// la ronde suivante quand la ronde en cours est complète. Retourne les nouvelles
+// rencontres à jouer. En élimination une nulle n'est pas enregistrée : la rencontre
+// doit être rejouée.
+func (t *Tournament) Report(id int, winner string) ([]*Pairing, error) {
+	if !t.Started {
+		return nil, ErrNotStarted
+	}
+	p := t.pairing(id)
+	if p == nil {
+		return nil, ErrUnknownPairing
+	}
+	if p.Done {
+		return nil, ErrAlreadyReported
+	}
+	if winner != "" && winner != p.A && winner != p.B {
+		return nil, ErrBadWinner
+	}
+	if winner == "" && t.isElimination() {
+		return []*Pairing{p}, nil
+	}
+	p.Done = true
+	p.Winner = winner
+	p.Draw = winner == ""
+	if t.Format == RoundRobin {
+		if len(t.Pending()) == 0 {
+			t.finish()
+		}
+		return nil, nil
+	}
+	for _, q := range t.Pairings {
+		if q.Round == t.Round && !q.Done {
+			return nil, nil
+		}
+	}
+	return t.nextRound(), nil
+}
+
+// Pending retourne les rencontres restant à jouer
+func (t *Tournament) Pending() []*Pairing {
+	var list []*Pairing
+	for _, p := range t.Pairings {
+		if !p.Done {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// Pairing retourne la rencontre d'identifiant id
+func (t *Tournament) pairing(id int) *Pairing {
+	for _, p := range t.Pairings {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (t *Tournament) isElimination() bool {
+	return t.Format == SingleElimination || t.Format == DoubleElimination
+}
+
+func (t *Tournament) add(round int, bracket, a, b string) *Pairing {
+	p := &Pairing{ID: len(t.Pairings) + 1, Round: round, Bracket: bracket, A: a, B: b}
+	if p.IsBye() {
+		// Un exempt compte comme une victoire
+		p.Done = true
+		p.Winner = a
+	}
+	t.Pairings = append(t.Pairings, p)
+	return p
+}
+
+// nextRound génère la ronde suivante selon le format, ou termine le tournoi
+func (t *Tournament) nextRound() []*Pairing {
+	if t.Finished {
+		return nil
+	}
+	before := len(t.Pairings)
+	t.Round++
+	switch t.Format {
+	case SingleElimination, DoubleElimination:
+		t.eliminationRound()
+	case Swiss:
+		if t.Round > t.Rounds {
+			t.Round--
+			t.finish()
+			return nil
+		}
+		t.swissRound()
+	}
+	if t.Finished {
+		return nil
+	}
+	created := t.Pairings[before:]
+	// Une ronde composée uniquement d'exempts se termine immédiatement
+	for _, p := range created {
+		if !p.Done {
+			return pendingOf(created)
+		}
+	}
+	return t.nextRound()
+}
+
+func pendingOf(list []*Pairing) []*Pairing {
+	var out []*Pairing
+	for _, p := range list {
+		if !p.Done {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// losses compte les défaites de chaque joueur (élimination)
+func (t *Tournament) losses() map[string]int {
+	l := make(map[string]int, len(t.Players))
+	for _, name := range t.Players {
+		l[name] = 0
+	}
+	for _, p := range t.Pairings {
+		if !p.Done || p.IsBye() || p.Draw {
+			continue
+		}
+		l[p.loser()]++
+	}
+	return l
+}
+
+func (p *Pairing) loser() string {
+	if p.Winner == p.A {
+		return p.B
+	}
+	return p.A
+}
+
+// seeded trie des joueurs par tête de série (ordre d'inscription)
+func (t *Tournament) seeded(names []string) []string {
+	seed := make(map[string]int, len(t.Players))
+	for i, name := range t.Players {
+		seed[name] = i
+	}
+	sort.Slice(names, func(i, j int) bool { return seed[names[i]] < seed[names[j]] })
+	return names
+}
+
+// eliminationRound apparie les joueurs encore en lice dans chaque tableau.
+// Au premier tour, les meilleures têtes de série sont exemptes pour ramener le
+// tableau principal à une puissance de deux.
+func (t *Tournament) eliminationRound() {
+	maxLosses := 1
+	if t.Format == DoubleElimination {
+		maxLosses = 2
+	}
+	losses := t.losses()
+	var winners, losers []string
+	for _, name := range t.Players {
+		switch losses[name] {
+		case 0:
+			winners = append(winners, name)
+		case 1:
+			if maxLosses == 2 {
+				losers = append(losers, name)
+			}
+		}
+	}
+	winners, losers = t.seeded(winners), t.seeded(losers)
+
+	switch {
+	case len(winners)+len(losers) <= 1:
+		// Un seul joueur encore en lice : c'est le champion
+		t.Round--
+		t.finish()
+		return
+	case len(winners) == 1 && len(losers) == 1:
+		// Grande finale (et finale « reset » si le champion du tableau principal perd)
+		t.add(t.Round, BracketFinal, winners[0], losers[0])
+		return
+	case len(winners) == 0 && len(losers) == 2:
+		t.add(t.Round, BracketFinal, losers[0], losers[1])
+		return
+	}
+
+	bracket := BracketWinners
+	if t.Round == 1 {
+		// Exempts du premier tour
+		size := 1
+		for size < len(winners) {
+			size *= 2
+		}
+		byes := size - len(winners)
+		for _, name := range winners[:byes] {
+			t.add(t.Round, bracket, name, "")
+		}
+		winners = winners[byes:]
+	}
+	pairFolded(t, bracket, winners)
+	if len(losers) > 1 || (len(losers) == 1 && len(winners) > 1) {
+		pairFolded(t, BracketLosers, losers)
+	}
+}
+
+// pairFolded apparie la meilleure tête de série avec la moins bonne, et ainsi de suite.
+// Si le nombre est impair, la meilleure tête de série est exempte.
+func pairFolded(t *Tournament, bracket string, names []string) {
+	if len(names)%2 == 1 {
+		t.add(t.Round, bracket, names[0], "")
+		names = names[1:]
+	}
+	for i := 0; i < len(names)/2; i++ {
+		t.add(t.Round, bracket, names[i], names[len(names)-1-i])
+	}
+}
+
+// generateRoundRobin génère toutes les rondes par la méthode du cercle
+func (t *Tournament) generateRoundRobin() {
+	names := append([]string(nil), t.Players...)
+	if len(names)%2 == 1 {
+		names = append(names, "") // Exempt
+	}
+	n := len(names)
+	for round := 1; round < n; round++ {
+		for i := 0; i < n/2; i++ {
+			a, b := names[i], names[n-1-i]
+			if a == "" {
+				a, b = b, a
+			}
+			t.add(round, BracketMain, a, b)
+		}
+		// Rotation : le premier joueur reste fixe
+		names = append([]string{names[0], names[n-1]}, names[1:n-1]...)
+	}
+	t.Round = 1
+}
+
+// swissRound apparie les joueurs de score proche sans revanche,
+// l'exempt revenant au moins bien classé qui n'en a pas encore eu.
+func (t *Tournament) swissRound() {
+	standings := t.Standings()
+	order := make([]string, len(standings))
+	for i, s := range standings {
+		order[i] = s.Player
+	}
+	if len(order)%2 == 1 {
+		for i := len(order) - 1; i >= 0; i-- {
+			if !t.hadBye(order[i]) {
+				t.add(t.Round, BracketMain, order[i], "")
+				order = append(order[:i], order[i+1:]...)
+				break
+			}
+		}
+	}
+	pairs, ok := t.pairWithoutRematch(order)
+	if !ok {
+		// Impossible d'éviter toutes les revanches : appariement dans l'ordre du classement
+		pairs = nil
+		for i := 0; i+1 < len(order); i += 2 {
+			pairs = append(pairs, [2]string{order[i], order[i+1]})
+		}
+	}
+	for _, p := range pairs {
+		t.add(t.Round, BracketMain, p[0], p[1])
+	}
+}
+
+// pairWithoutRematch apparie récursivement le premier joueur avec le suivant le plus proche
+// au classement qu'il n'a pas encore rencontré.
+func (t *Tournament) pairWithoutRematch(order []string) ([][2]string, bool) {
+	if len(order) == 0 {
+		return nil, true
+	}
+	first := order[0]
+	for i := 1; i < len(order); i++ {
+		if t.played(first, order[i]) {
+			continue
+		}
+		rest := make([]string, 0, len(order)-2)
+		rest = append(rest, order[1:i]...)
+		rest = append(rest, order[i+1:]...)
+		if pairs, ok := t.pairWithoutRematch(rest); ok {
+			return append([][2]string{{first, order[i]}}, pairs...), true
+		}
+	}
+	return nil, false
+}
+
+func (t *Tournament) played(a, b string) bool {
+	for _, p := range t.Pairings {
+		if (p.A == a && p.B == b) || (p.A == b && p.B == a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tournament) hadBye(name string) bool {
+	for _, p := range t.Pairings {
+		if p.IsBye() && p.A == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tournament) finish() {
+	t.Finished = true
+	if t.isElimination() {
+		// Le champion est le vainqueur de la dernière rencontre jouée
+		for i := len(t.Pairings) - 1; i >= 0; i-- {
+			if p := t.Pairings[i]; p.Done && !p.IsBye() {
+				t.Champion = p.Winner
+				return
+			}
+		}
+	}
+	if s := t.Standings(); len(s) > 0 {
+		t.Champion = s[0].Player
+	}
+}
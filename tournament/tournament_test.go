@@ -0,0 +1,188 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newWithPlayers(t *testing.T, format string, n int) *Tournament {
+	t.Helper()
+	tr, err := New("T1", "Test", "multi-classique", format, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= n; i++ {
+		if err := tr.Register(fmt.Sprintf("P%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tr
+}
+
+// playAll joue toutes les rencontres en faisant gagner la meilleure tête de série
+func playAll(t *testing.T, tr *Tournament) {
+	t.Helper()
+	seed := map[string]int{}
+	for i, p := range tr.Players {
+		seed[p] = i
+	}
+	pending, err := tr.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for guard := 0; len(pending) > 0 && guard < 1000; guard++ {
+		p := pending[0]
+		winner := p.A
+		if seed[p.B] < seed[p.A] {
+			winner = p.B
+		}
+		next, err := tr.Report(p.ID, winner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending = append(pending[1:], next...)
+	}
+	if !tr.Finished {
+		t.Fatalf("tournoi non terminé : %+v", tr.Pending())
+	}
+}
+
+func TestSingleEliminationWithByes(t *testing.T) {
+	tr := newWithPlayers(t, SingleElimination, 5)
+	playAll(t, tr)
+	if tr.Champion != "P1" {
+		t.Fatalf("champion = %q, attendu P1", tr.Champion)
+	}
+	byes := 0
+	for _, p := range tr.Pairings {
+		if p.IsBye() {
+			byes++
+			if p.Round != 1 {
+				t.Fatalf("exempt hors premier tour : %+v", p)
+			}
+		}
+	}
+	if byes != 3 {
+		t.Fatalf("byes = %d, attendu 3 (tableau de 8)", byes)
+	}
+}
+
+func TestDoubleEliminationBracketReset(t *testing.T) {
+	tr := newWithPlayers(t, DoubleElimination, 4)
+	pending, err := tr.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// P1 gagne tout sauf la grande finale, perdue contre le vainqueur du tableau perdants
+	for guard := 0; len(pending) > 0 && guard < 100; guard++ {
+		p := pending[0]
+		winner := p.A
+		if p.Bracket == BracketFinal && p.A == "P1" {
+			winner = p.B
+		}
+		next, err := tr.Report(p.ID, winner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending = append(pending[1:], next...)
+	}
+	finals := 0
+	for _, p := range tr.Pairings {
+		if p.Bracket == BracketFinal {
+			finals++
+		}
+	}
+	if finals != 2 {
+		t.Fatalf("finales = %d, attendu 2 (finale reset)", finals)
+	}
+	for name, l := range tr.losses() {
+		if name != tr.Champion && l < 2 {
+			t.Fatalf("%s éliminé avec %d défaite(s)", name, l)
+		}
+	}
+}
+
+func TestSwissAvoidsRematches(t *testing.T) {
+	tr := newWithPlayers(t, Swiss, 6)
+	playAll(t, tr)
+	if tr.Rounds != 3 {
+		t.Fatalf("rondes = %d, attendu 3", tr.Rounds)
+	}
+	seen := map[string]bool{}
+	for _, p := range tr.Pairings {
+		key := p.A + "-" + p.B
+		if p.B < p.A {
+			key = p.B + "-" + p.A
+		}
+		if seen[key] {
+			t.Fatalf("revanche %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestRoundRobinOddPlayers(t *testing.T) {
+	tr := newWithPlayers(t, RoundRobin, 5)
+	playAll(t, tr)
+	games := 0
+	for _, p := range tr.Pairings {
+		if !p.IsBye() {
+			games++
+		}
+	}
+	if games != 10 {
+		t.Fatalf("parties = %d, attendu 10", games)
+	}
+	s := tr.Standings()
+	if s[0].Player != "P1" || s[0].Points != 5 {
+		t.Fatalf("premier = %+v", s[0])
+	}
+}
+
+func TestTieBreaks(t *testing.T) {
+	tr := newWithPlayers(t, RoundRobin, 3)
+	if _, err := tr.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// P3 bat P1, P1 bat P2, P2 bat P3 : égalité parfaite sauf exempts identiques
+	results := map[string]string{"P1-P2": "P1", "P2-P3": "P2", "P1-P3": "P3"}
+	for _, p := range tr.Pending() {
+		key := p.A + "-" + p.B
+		if p.B < p.A {
+			key = p.B + "-" + p.A
+		}
+		if _, err := tr.Report(p.ID, results[key]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := tr.Standings()
+	for _, row := range s {
+		if row.Points != 2 { // 1 victoire + 1 exempt
+			t.Fatalf("points %+v", row)
+		}
+	}
+	// Égalité totale : la tête de série départage
+	if s[0].Player != "P1" {
+		t.Fatalf("ordre = %v", s)
+	}
+
+	// Confrontation directe entre deux joueurs à égalité
+	tr = newWithPlayers(t, RoundRobin, 4)
+	if _, err := tr.Start(); err != nil {
+		t.Fatal(err)
+	}
+	results = map[string]string{"P1-P2": "P2", "P1-P3": "P1", "P1-P4": "P1", "P2-P3": "P3", "P2-P4": "P2", "P3-P4": "P4"}
+	for _, p := range tr.Pending() {
+		key := p.A + "-" + p.B
+		if p.B < p.A {
+			key = p.B + "-" + p.A
+		}
+		if _, err := tr.Report(p.ID, results[key]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s = tr.Standings()
+	if s[0].Player != "P2" || s[1].Player != "P1" {
+		t.Fatalf("P2 a battu P1 et doit le devancer : %v", s)
+	}
+}
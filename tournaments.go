@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"power4/game"
+	"power4/tournament"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ---------------- TOURNOIS ----------------
+
+var (
+	tournaments   = make(map[string]*tournament.Tournament)
+	tournamentsMu sync.Mutex
+	// Clé secrète de chaque joueur inscrit, par tournoi : c'est son jeton de siège dans toutes
+	// ses rencontres. Elle n'apparaît ni dans la liste des tournois ni dans le classement.
+	tournamentKeys = make(map[string]map[string]string)
+)
+
+// savedTournament : tournoi tel que sauvegardé, avec les clés de ses joueurs
+type savedTournament struct {
+	*tournament.Tournament
+	Keys map[string]string `json:"keys,omitempty"`
+}
+
+func tournamentsDir() string { return filepath.Join(dataDir, "tournaments") }
+
+func saveTournament(t *tournament.Tournament) {
+	data, err := json.MarshalIndent(savedTournament{t, tournamentKeys[t.ID]}, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(tournamentsDir(), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(tournamentsDir(), t.ID+".json"), data, 0o644)
+	}
+	if err != nil {
+		logger.Error("sauvegarde du tournoi impossible", "tournament", t.ID, "err", err)
+	}
+}
+
+// loadTournaments recharge les tournois et recrée les parties des rencontres en attente
+func loadTournaments() {
+	files, _ := filepath.Glob(filepath.Join(tournamentsDir(), "*.json"))
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		saved := savedTournament{Tournament: &tournament.Tournament{}}
+		if err := json.Unmarshal(data, &saved); err != nil {
+			logger.Warn("sauvegarde de tournoi invalide", "file", f, "err", err)
+			continue
+		}
+		t := saved.Tournament
+		tournaments[t.ID] = t
+		tournamentKeys[t.ID] = saved.Keys
+		for _, pr := range t.Pending() {
+			partiesMu.Lock()
+			_, exists := parties[pr.PartyCode]
+			partiesMu.Unlock()
+			if !exists {
+				startPairingParty(t, pr)
+			}
+		}
+	}
+}
+
+// startPairingParty crée la partie d'une rencontre : A joue Rouge, B joue Jaune, chacun avec
+// sa clé de tournoi comme jeton de siège. tournamentsMu doit être verrouillé.
+func startPairingParty(t *tournament.Tournament, pr *tournament.Pairing) {
+	p := newModeParty(t.Mode, cfg.Board.DefaultRows, cfg.Board.DefaultCols, nil)
+	p.Tournament = t.ID
+	p.PairingID = pr.ID
+	for team, player := range map[string]string{"R": pr.A, "Y": pr.B} {
+		if key := tournamentKeys[t.ID][player]; key != "" {
+			p.seatWithToken(team, player, key)
+		} else {
+			// Tournoi sauvegardé sans clés : siège réservé, que personne ne peut occuper
+			p.logger().Warn("joueur de tournoi sans clé", "tournament", t.ID, "player", player)
+			p.takeSeat(team, player, "")
+		}
+	}
+	pr.PartyCode = p.Code
+
+	partiesMu.Lock()
+	parties[p.Code] = p
+	partiesMu.Unlock()
+	p.logger().Info("rencontre de tournoi lancée", "tournament", t.ID, "round", pr.Round, "player_a", pr.A, "player_b", pr.B)
+}
+
+// reportTournamentResult transmet au tournoi le vainqueur d'une partie terminée
+// (winner vide pour une nulle) et lance les rencontres suivantes.
+func reportTournamentResult(id string, pairingID int, winner string) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	t, ok := tournaments[id]
+	if !ok {
+		return
+	}
+	next, err := t.Report(pairingID, winner)
+	if err != nil {
+		logger.Warn("résultat de tournoi refusé", "tournament", id, "pairing", pairingID, "err", err)
+		return
+	}
+	for _, pr := range next {
+		startPairingParty(t, pr)
+	}
+	if t.Finished {
+		logger.Info("tournoi terminé", "tournament", id, "champion", t.Champion)
+	}
+	saveTournament(t)
+}
+
+// lookupTournament retourne le tournoi demandé (paramètre id). tournamentsMu doit être verrouillé.
+func lookupTournament(w http.ResponseWriter, r *http.Request) *tournament.Tournament {
+	t, ok := tournaments[strings.ToUpper(r.FormValue("id"))]
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "Tournoi introuvable")
+		return nil
+	}
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func tournamentCreateHandler(w http.ResponseWriter, r *http.Request) {
+	mode := r.FormValue("mode")
+	if mode == "" {
+		mode = "multi-classique"
+	}
+	// Une rencontre se joue à deux, chacun avec sa clé : pas de mode solo ni de variante inconnue
+	prefix, variant, _ := strings.Cut(mode, "-")
+	if _, ok := game.Lookup(variant); prefix != "multi" || !ok {
+		writeJSONError(w, http.StatusBadRequest, "Mode de tournoi invalide (multi-<variante>)")
+		return
+	}
+	rounds, _ := strconv.Atoi(r.FormValue("rounds"))
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = "Tournoi"
+	}
+
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	id := generateCode()
+	t, err := tournament.New(id, name, mode, r.FormValue("format"), rounds)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tournaments[id] = t
+	tournamentKeys[id] = make(map[string]string)
+	saveTournament(t)
+	requestLogger(r).Info("tournoi créé", "tournament", id, "format", t.Format, "mode", mode)
+	writeJSON(w, map[string]string{"id": id})
+}
+
+func tournamentRegisterHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	t := lookupTournament(w, r)
+	if t == nil {
+		return
+	}
+	player := strings.TrimSpace(r.FormValue("player"))
+	if player == "" {
+		writeJSONError(w, http.StatusBadRequest, "Nom du joueur manquant")
+		return
+	}
+	if err := t.Register(player); err != nil {
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if tournamentKeys[t.ID] == nil {
+		tournamentKeys[t.ID] = make(map[string]string)
+	}
+	key := newToken()
+	tournamentKeys[t.ID][player] = key
+	saveTournament(t)
+	// La clé n'est donnée qu'une fois, au joueur qui s'inscrit
+	writeJSON(w, map[string]interface{}{"success": true, "players": t.Players, "key": key})
+}
+
+func tournamentStartHandler(w http.ResponseWriter, r *http.Request) {
+	if refuseWhileDraining(w) {
+		return
+	}
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	t := lookupTournament(w, r)
+	if t == nil {
+		return
+	}
+	pending, err := t.Start()
+	if err != nil {
+		writeJSONError(w, http.StatusConflict, err.Error())
+		return
+	}
+	for _, pr := range pending {
+		startPairingParty(t, pr)
+	}
+	saveTournament(t)
+	writeJSON(w, map[string]interface{}{"success": true, "pairings": pending})
+}
+
+func tournamentStandingsHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	if t := lookupTournament(w, r); t != nil {
+		writeJSON(w, map[string]interface{}{
+			"id":        t.ID,
+			"finished":  t.Finished,
+			"champion":  t.Champion,
+			"standings": t.Standings(),
+		})
+	}
+}
+
+func tournamentBracketHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	if t := lookupTournament(w, r); t != nil {
+		writeJSON(w, map[string]interface{}{
+			"id":      t.ID,
+			"format":  t.Format,
+			"round":   t.Round,
+			"bracket": t.Bracket(),
+		})
+	}
+}
+
+// sortedTournaments retourne les tournois triés par identifiant. tournamentsMu doit être verrouillé.
+func sortedTournaments() []*tournament.Tournament {
+	list := make([]*tournament.Tournament, 0, len(tournaments))
+	for _, t := range tournaments {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func tournamentListHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	writeJSON(w, sortedTournaments())
+}
+
+func tournamentPageHandler(w http.ResponseWriter, r *http.Request) {
+	tournamentsMu.Lock()
+	defer tournamentsMu.Unlock()
+	data := struct {
+		Tournaments []*tournament.Tournament
+		Current     *tournament.Tournament
+		Standings   []tournament.Standing
+		Bracket     map[string][]tournament.Round
+	}{Tournaments: sortedTournaments()}
+	if t, ok := tournaments[strings.ToUpper(r.URL.Query().Get("id"))]; ok {
+		data.Current = t
+		data.Standings = t.Standings()
+		data.Bracket = t.Bracket()
+	}
+	if err := pages.Execute(w, "tournament.html", data); err != nil {
+		requestLogger(r).Error("rendu du template impossible", "template", "tournament.html", "err", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
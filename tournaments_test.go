@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// tournamentCall appelle un handler de tournoi avec un formulaire POST et décode la réponse
+func tournamentCall(t *testing.T, h http.HandlerFunc, form url.Values) map[string]interface{} {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/tournament", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h(w, req)
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("code %d (%v)", w.Code, resp)
+	}
+	return resp
+}
+
+func TestTournamentKeysAreSeatTokens(t *testing.T) {
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+	limits = newRateLimiters(defaultConfig().RateLimits)
+
+	id := tournamentCall(t, tournamentCreateHandler, url.Values{"format": {"single"}, "mode": {"multi-classique"}})["id"].(string)
+	keys := make(map[string]string)
+	for _, player := range []string{"Alice", "Bob"} {
+		resp := tournamentCall(t, tournamentRegisterHandler, url.Values{"id": {id}, "player": {player}})
+		keys[player], _ = resp["key"].(string)
+		if keys[player] == "" {
+			t.Fatalf("pas de clé pour %s : %v", player, resp)
+		}
+	}
+	tournamentCall(t, tournamentStartHandler, url.Values{"id": {id}})
+
+	tournamentsMu.Lock()
+	pr := tournaments[id].Pending()[0]
+	tournamentsMu.Unlock()
+	partiesMu.Lock()
+	p := parties[pr.PartyCode]
+	partiesMu.Unlock()
+	t.Cleanup(func() {
+		partiesMu.Lock()
+		delete(parties, p.Code)
+		partiesMu.Unlock()
+	})
+	if p.Tokens[keys[pr.A]] != "R" || p.Tokens[keys[pr.B]] != "Y" {
+		t.Fatalf("jetons de la rencontre %v, clés %v", p.Tokens, keys)
+	}
+
+	// Sans la clé du joueur, pas de coup ; la clé de l'adversaire ne joue pas à sa place
+	if w := postMove(p.Code, "", 3); w.Code != http.StatusForbidden {
+		t.Errorf("sans jeton : code %d", w.Code)
+	}
+	if w := postMove(p.Code, keys[pr.B], 3); w.Code != http.StatusForbidden {
+		t.Errorf("clé de %s au tour de %s : code %d", pr.B, pr.A, w.Code)
+	}
+	if w := postMove(p.Code, keys[pr.A], 3); w.Code != http.StatusOK {
+		t.Fatalf("clé de %s : code %d (%s)", pr.A, w.Code, w.Body)
+	}
+
+	// Les clés survivent à un redémarrage, sans apparaître dans la liste publique
+	tournamentsMu.Lock()
+	delete(tournaments, id)
+	delete(tournamentKeys, id)
+	tournamentsMu.Unlock()
+	loadTournaments()
+	tournamentsMu.Lock()
+	reloaded := tournamentKeys[id]
+	tournamentsMu.Unlock()
+	if reloaded["Alice"] != keys["Alice"] || reloaded["Bob"] != keys["Bob"] {
+		t.Fatalf("clés rechargées %v, attendu %v", reloaded, keys)
+	}
+	w := httptest.NewRecorder()
+	tournamentListHandler(w, httptest.NewRequest(http.MethodGet, "/api/tournament/list", nil))
+	if strings.Contains(w.Body.String(), keys["Alice"]) {
+		t.Fatal("clé de joueur publiée dans la liste des tournois")
+	}
+}
+
+func TestTournamentModeMustBeMulti(t *testing.T) {
+	dir := dataDir
+	dataDir = t.TempDir()
+	t.Cleanup(func() { dataDir = dir })
+	for _, mode := range []string{"solo-classique", "multi-inconnue", "classique", "multi-"} {
+		form := url.Values{"format": {"single"}, "mode": {mode}}
+		req := httptest.NewRequest(http.MethodPost, "/api/tournament/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		tournamentCreateHandler(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("mode %q : code %d", mode, w.Code)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestTemplateRenders42Cells s'assure que le plateau (6x7) génère bien 42 cellules (<td>),
+// rendu par gameHandler avec ses propres données
+func TestTemplateRenders42Cells(t *testing.T) {
+	p := createParty(t, "variant=classique")
+	w := httptest.NewRecorder()
+	gameHandler(w, httptest.NewRequest(http.MethodGet, "/game?code="+p.Code+"&team=R", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("page de jeu : code %d", w.Code)
+	}
+	td := strings.Count(w.Body.String(), "<td")
+	if td != 42 {
+		t.Fatalf("expected 42 <td> elements, got %d", td)
+	}
+}
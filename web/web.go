@@ -0,0 +1,140 @@
+// Package web embarque les ressources de l'interface (templates HTML, CSS/JS, images,
+// particles.js) pour que le serveur fonctionne quel que soit son répertoire de lancement.
+// En développement, un répertoire sur disque de même structure peut les remplacer : les
+// fichiers sont alors relus à chaque requête.
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+// FS contient les ressources embarquées : templates/, Images/ et particles.js
+//
+//go:embed templates Images particles.js-master/particles.js
+var FS embed.FS
+
+// Assets retourne les ressources à servir : le répertoire dir s'il est fourni, sinon FS
+func Assets(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
+	}
+	return FS
+}
+
+// Templates : pages HTML de templates/*.html, relues à chaque rendu si reload est vrai
+type Templates struct {
+	fsys   fs.FS
+	funcs  template.FuncMap
+	reload bool
+
+	mu  sync.Mutex
+	set *template.Template
+}
+
+// NewTemplates analyse tous les templates de fsys
+func NewTemplates(fsys fs.FS, funcs template.FuncMap, reload bool) (*Templates, error) {
+	t := &Templates{fsys: fsys, funcs: funcs, reload: reload}
+	set, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+	t.set = set
+	return t, nil
+}
+
+// MustTemplates est comme NewTemplates mais panique en cas d'erreur
+func MustTemplates(fsys fs.FS, funcs template.FuncMap, reload bool) *Templates {
+	t, err := NewTemplates(fsys, funcs, reload)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (t *Templates) parse() (*template.Template, error) {
+	return template.New("").Funcs(t.funcs).ParseFS(t.fsys, "templates/*.html")
+}
+
+// Execute rend la page name (ex : "index.html")
+func (t *Templates) Execute(w io.Writer, name string, data interface{}) error {
+	t.mu.Lock()
+	set := t.set
+	if t.reload {
+		fresh, err := t.parse()
+		if err != nil {
+			t.mu.Unlock()
+			return err
+		}
+		t.set, set = fresh, fresh
+	}
+	t.mu.Unlock()
+	return set.ExecuteTemplate(w, name, data)
+}
+
+// fileServer sert les fichiers d'un sous-répertoire avec ETag et Cache-Control
+type fileServer struct {
+	fsys   fs.FS
+	maxAge string
+
+	mu    sync.Mutex
+	etags map[string]string // Empreintes calculées, conservées si les fichiers ne changent pas
+}
+
+// FileServer sert les fichiers du répertoire root de fsys. Si cache est vrai (ressources
+// embarquées), les empreintes sont calculées une seule fois et les navigateurs peuvent
+// garder les fichiers une heure ; sinon ils doivent revalider à chaque chargement.
+func FileServer(fsys fs.FS, root string, cache bool) http.Handler {
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		panic(err)
+	}
+	s := &fileServer{fsys: sub, maxAge: "no-cache"}
+	if cache {
+		s.maxAge = "public, max-age=3600"
+		s.etags = make(map[string]string)
+	}
+	return s
+}
+
+func (s *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)[1:]
+	if name == "" {
+		name = "."
+	}
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("ETag", s.etag(name, data))
+	w.Header().Set("Cache-Control", s.maxAge)
+	// ServeContent gère If-None-Match (304), les plages et le Content-Type
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+}
+
+func (s *fileServer) etag(name string, data []byte) string {
+	if s.etags != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if tag, ok := s.etags[name]; ok {
+			return tag
+		}
+	}
+	sum := sha256.Sum256(data)
+	tag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	if s.etags != nil {
+		s.etags[name] = tag
+	}
+	return tag
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileServerETag(t *testing.T) {
+	h := FileServer(FS, "templates", true)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/font.css", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("code %d, ETag %q", w.Code, etag)
+	}
+	if cc := w.Header().Get("Cache-Control"); !strings.Contains(cc, "max-age") {
+		t.Fatalf("Cache-Control = %q", cc)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/font.css", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("revalidation : code %d, attendu 304", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/../go.mod", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("chemin hors du répertoire : code %d", w.Code)
+	}
+}
+
+func TestTemplatesReloadFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	page := filepath.Join(dir, "templates", "page.html")
+	write := func(s string) {
+		if err := os.WriteFile(page, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("v1")
+	tmpl, err := NewTemplates(Assets(dir), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("v2")
+	var out strings.Builder
+	if err := tmpl.Execute(&out, "page.html", nil); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "v2" {
+		t.Fatalf("rendu %q, attendu la version modifiée sur disque", out.String())
+	}
+}